@@ -0,0 +1,342 @@
+package set
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Bag represents an unordered collection of values in which each value may occur more than once, also
+// known as a multiset.  Each element of a bag has a multiplicity, which is the number of times it
+// occurs, and elements with a multiplicity of zero are not members of the bag.
+//
+// Like Set, a Bag is safe for concurrent use by multiple goroutines.
+type Bag struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Multiplicity of each element, which is always greater than zero
+	m map[interface{}]int
+}
+
+// NewBag creates a new Bag, and initializes its internal map, optionally adding initial elements to the
+// bag.  Each occurrence of a value in the initializer adds one to its multiplicity.
+func NewBag(values ...interface{}) *Bag {
+	// Initialize bag
+	s := Bag{
+		m: make(map[interface{}]int, len(values)),
+	}
+
+	// If items are specified in the initializer, immediately add them to the bag
+	for _, v := range values {
+		s.m[v]++
+	}
+
+	return &s
+}
+
+// BagFromSet creates a new Bag containing each element of a Set exactly once
+func BagFromSet(t *Set) *Bag {
+	// Lock set for read
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	// Copy set into a new bag
+	s := &Bag{
+		m: make(map[interface{}]int, len(t.m)),
+	}
+	for k := range t.m {
+		s.m[k] = 1
+	}
+
+	return s
+}
+
+// Add inserts n occurrences of an element into the bag, returning the new multiplicity of the element.
+// If n is zero or negative, the bag is not modified.  Multiplicities saturate at math.MaxInt, rather
+// than overflowing.
+func (s *Bag) Add(value interface{}, n int) int {
+	// Lock bag for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Add occurrences of value to bag
+	if n > 0 {
+		s.m[value] = addCounts(s.m[value], n)
+	}
+
+	return s.m[value]
+}
+
+// Clone copies the current bag into a new, identical bag
+func (s *Bag) Clone() *Bag {
+	// Lock bag for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy bag into a new bag
+	out := &Bag{
+		m: make(map[interface{}]int, len(s.m)),
+	}
+	for k, n := range s.m {
+		out.m[k] = n
+	}
+
+	return out
+}
+
+// Count returns the multiplicity of an element in the bag, or zero if it is not a member
+func (s *Bag) Count(value interface{}) int {
+	// Lock bag for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.m[value]
+}
+
+// Counts returns a map of each distinct element in the bag to its multiplicity
+func (s *Bag) Counts() map[interface{}]int {
+	// Lock bag for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy multiplicities into a new map
+	counts := make(map[interface{}]int, len(s.m))
+	for k, n := range s.m {
+		counts[k] = n
+	}
+
+	return counts
+}
+
+// Difference returns a bag in which the multiplicity of each element is its multiplicity in this bag,
+// less its multiplicity in the parameter bag.  Elements whose multiplicity falls to zero or below are
+// not members of the resulting bag.
+func (s *Bag) Difference(t *Bag) *Bag {
+	return s.combine(t, false, func(x int, y int) int {
+		return x - y
+	})
+}
+
+// Distinct returns the number of distinct elements in the bag, ignoring multiplicity
+func (s *Bag) Distinct() int {
+	// Lock bag for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.m)
+}
+
+// Enumerate returns a slice of all distinct elements in the bag.  Use Count or Counts to determine
+// the multiplicity of each element.
+func (s *Bag) Enumerate() []interface{} {
+	// Lock bag for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice
+	values := make([]interface{}, 0, len(s.m))
+	for k := range s.m {
+		values = append(values, k)
+	}
+
+	return values
+}
+
+// Equal returns whether or not two bags contain exactly the same elements, with the same multiplicities
+func (s *Bag) Equal(t *Bag) bool {
+	// Lock both bags for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	if len(s.m) != len(t.m) {
+		return false
+	}
+
+	// Compare multiplicities of every element
+	for k, n := range s.m {
+		if t.m[k] != n {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Has checks for membership of an element in the bag
+func (s *Bag) Has(value interface{}) bool {
+	return s.Count(value) > 0
+}
+
+// Intersection returns a bag in which the multiplicity of each element is the smaller of its
+// multiplicities in this bag and the parameter bag
+func (s *Bag) Intersection(t *Bag) *Bag {
+	return s.combine(t, false, func(x int, y int) int {
+		return min(x, y)
+	})
+}
+
+// Remove destroys up to n occurrences of an element in the bag, returning the new multiplicity of the
+// element.  If n is zero or negative, the bag is not modified.
+func (s *Bag) Remove(value interface{}, n int) int {
+	// Lock bag for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence
+	count, ok := s.m[value]
+	if !ok || n <= 0 {
+		return count
+	}
+
+	// Remove occurrences of value from bag, destroying the element once none remain
+	if n >= count {
+		delete(s.m, value)
+		return 0
+	}
+
+	s.m[value] = count - n
+	return count - n
+}
+
+// Size returns the total number of elements in the bag, counting each occurrence of an element.  The
+// size saturates at math.MaxInt, rather than overflowing.
+func (s *Bag) Size() int {
+	// Lock bag for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Sum multiplicities of all elements
+	size := 0
+	for _, n := range s.m {
+		size = addCounts(size, n)
+	}
+
+	return size
+}
+
+// String returns a string representation of this bag, with elements in canonical sorted order.  Each
+// element is printed once, followed by its multiplicity if it occurs more than once, such as a×3.
+func (s *Bag) String() string {
+	// Gather a snapshot of all multiplicities, and sort the elements
+	counts := s.Counts()
+	values := make([]interface{}, 0, len(counts))
+	for k := range counts {
+		values = append(values, k)
+	}
+	SortElements(values)
+
+	// Check for empty bag, print symbol if empty
+	if len(values) == 0 {
+		return "{ Ø }"
+	}
+
+	// Print identifier
+	var b strings.Builder
+	b.WriteString("{ ")
+
+	// Print all elements, with their multiplicities
+	for _, v := range values {
+		b.WriteString(formatElement(v, false))
+		if n := counts[v]; n > 1 {
+			b.WriteString("×")
+			b.WriteString(strconv.Itoa(n))
+		}
+		b.WriteString(" ")
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+// Subset determines if a parameter bag is contained within this bag, returning true if every element
+// of the parameter bag occurs in this bag at least as many times, or false if it does not
+func (s *Bag) Subset(t *Bag) bool {
+	// Lock both bags for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Check that no element occurs more often in the parameter bag
+	for k, n := range t.m {
+		if s.m[k] < n {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Sum returns a bag in which the multiplicity of each element is the total of its multiplicities in
+// this bag and the parameter bag, saturating at math.MaxInt
+func (s *Bag) Sum(t *Bag) *Bag {
+	return s.combine(t, true, addCounts)
+}
+
+// ToSet returns a Set containing each distinct element of the bag, discarding multiplicity
+func (s *Bag) ToSet() *Set {
+	// Lock bag for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy bag into a new set
+	outSet := &Set{
+		m: make(map[interface{}]struct{}, len(s.m)),
+	}
+	for k := range s.m {
+		outSet.m[k] = struct{}{}
+	}
+
+	return outSet
+}
+
+// Union returns a bag in which the multiplicity of each element is the larger of its multiplicities
+// in this bag and the parameter bag
+func (s *Bag) Union(t *Bag) *Bag {
+	return s.combine(t, true, func(x int, y int) int {
+		return max(x, y)
+	})
+}
+
+// combine creates a new bag by applying a function to the multiplicities of each element in the
+// current bag and the parameter bag.  If both is false, only elements of the current bag are
+// considered.  Elements whose resulting multiplicity is zero or below are not members of the new bag.
+func (s *Bag) combine(t *Bag, both bool, fn func(int, int) int) *Bag {
+	// Lock both bags for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	out := &Bag{
+		m: make(map[interface{}]int),
+	}
+
+	// Combine multiplicities of elements in the current bag
+	for k, n := range s.m {
+		if c := fn(n, t.m[k]); c > 0 {
+			out.m[k] = c
+		}
+	}
+
+	// Combine multiplicities of elements only in the parameter bag
+	if both {
+		for k, n := range t.m {
+			if _, ok := s.m[k]; ok {
+				continue
+			}
+
+			if c := fn(0, n); c > 0 {
+				out.m[k] = c
+			}
+		}
+	}
+
+	return out
+}
+
+// addCounts returns the sum of two non-negative multiplicities, saturating at math.MaxInt
+func addCounts(x int, y int) int {
+	if x > math.MaxInt-y {
+		return math.MaxInt
+	}
+
+	return x + y
+}
@@ -0,0 +1,173 @@
+package set
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestBagAddRemove verifies that the bag.Add(), bag.Remove() and bag.Count() methods are working properly
+func TestBagAddRemove(t *testing.T) {
+	log.Println("TestBagAddRemove()")
+
+	b := NewBag("a", "a", "b")
+	if b.Count("a") != 2 || b.Count("b") != 1 || b.Count("c") != 0 {
+		t.Fatalf("set.NewBag() - unexpected counts: %v", b.Counts())
+	}
+
+	// Create a table of operations and expected multiplicities
+	var tests = []struct {
+		add    bool
+		value  interface{}
+		n      int
+		result int
+	}{
+		// Add several occurrences
+		{true, "a", 3, 5},
+		// Adding nothing has no effect
+		{true, "a", 0, 5},
+		{true, "c", -1, 0},
+		// Remove some occurrences
+		{false, "a", 2, 3},
+		// Removing nothing has no effect
+		{false, "a", -2, 3},
+		// Removing more occurrences than exist destroys the element
+		{false, "b", 10, 0},
+		// Removing a missing element has no effect
+		{false, "c", 1, 0},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		var count int
+		if test.add {
+			count = b.Add(test.value, test.n)
+		} else {
+			count = b.Remove(test.value, test.n)
+		}
+
+		if count != test.result || b.Count(test.value) != test.result {
+			t.Fatalf("set.Bag - unexpected count for %v: %d != %d", test.value, count, test.result)
+		}
+		if b.Has(test.value) != (test.result > 0) {
+			t.Fatalf("set.Bag.Has(%v) - unexpected result: %t", test.value, b.Has(test.value))
+		}
+	}
+
+	if b.Size() != 3 || b.Distinct() != 1 {
+		t.Fatalf("set.Bag - unexpected size: %d, %d", b.Size(), b.Distinct())
+	}
+	if b.String() != "{ a×3 }" {
+		t.Fatalf("set.Bag.String() - unexpected result: %s", b.String())
+	}
+	if s := NewBag("b", 1, "a", "b").String(); s != "{ 1 a b×2 }" {
+		t.Fatalf("set.Bag.String() - unexpected result: %s", s)
+	}
+}
+
+// TestBagOverflow verifies that multiplicities saturate rather than overflow, and that bags with huge
+// multiplicities can be printed
+func TestBagOverflow(t *testing.T) {
+	log.Println("TestBagOverflow()")
+
+	b := NewBag("a")
+	if n := b.Add("a", math.MaxInt); n != math.MaxInt {
+		t.Fatalf("set.Bag.Add() - unexpected result: %d", n)
+	}
+	if n := b.Add("a", 1); n != math.MaxInt {
+		t.Fatalf("set.Bag.Add() - unexpected result: %d", n)
+	}
+
+	b.Add("b", 2)
+	if b.Size() != math.MaxInt {
+		t.Fatalf("set.Bag.Size() - unexpected result: %d", b.Size())
+	}
+	if n := b.Sum(b).Count("a"); n != math.MaxInt {
+		t.Fatalf("set.Bag.Sum() - unexpected result: %d", n)
+	}
+	if s := b.String(); s != "{ a×"+strconv.Itoa(math.MaxInt)+" b×2 }" {
+		t.Fatalf("set.Bag.String() - unexpected result: %s", s)
+	}
+}
+
+// TestBagAlgebra verifies that the bag.Union(), bag.Sum(), bag.Intersection() and bag.Difference()
+// methods are working properly
+func TestBagAlgebra(t *testing.T) {
+	log.Println("TestBagAlgebra()")
+
+	x := NewBag(1, 1, 1, 2, 3, 3)
+	y := NewBag(1, 2, 2, 3, 3, 4)
+
+	// Create a table of tests and expected results
+	var tests = []struct {
+		name   string
+		result *Bag
+		target *Bag
+	}{
+		{"Union", x.Union(y), NewBag(1, 1, 1, 2, 2, 3, 3, 4)},
+		{"Sum", x.Sum(y), NewBag(1, 1, 1, 1, 2, 2, 2, 3, 3, 3, 3, 4)},
+		{"Intersection", x.Intersection(y), NewBag(1, 2, 3, 3)},
+		{"Difference", x.Difference(y), NewBag(1, 1)},
+		{"Difference", y.Difference(x), NewBag(2, 4)},
+		{"Union", x.Union(NewBag()), x},
+		{"Intersection", x.Intersection(NewBag()), NewBag()},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.Bag.%s() - bags not equal: %s != %s", test.name, test.result, test.target)
+		}
+	}
+
+	// Check containment
+	if !x.Subset(NewBag(1, 1, 3)) || x.Subset(NewBag(2, 2)) || !x.Subset(NewBag()) {
+		t.Fatalf("set.Bag.Subset() - unexpected result")
+	}
+	if x.Equal(x.Sum(NewBag(1))) || !x.Equal(x.Clone()) {
+		t.Fatalf("set.Bag.Equal() - unexpected result")
+	}
+}
+
+// TestBagSet verifies that bags are properly converted to and from sets
+func TestBagSet(t *testing.T) {
+	log.Println("TestBagSet()")
+
+	b := BagFromSet(New(1, 2, 3))
+	if !b.Equal(NewBag(1, 2, 3)) {
+		t.Fatalf("set.BagFromSet() - unexpected result: %s", b)
+	}
+
+	b.Add(1, 5)
+	if s := b.ToSet(); !s.Equal(New(1, 2, 3)) {
+		t.Fatalf("set.Bag.ToSet() - unexpected result: %s", s)
+	}
+}
+
+// TestBagConcurrent verifies that bags are safe for concurrent use
+func TestBagConcurrent(t *testing.T) {
+	log.Println("TestBagConcurrent()")
+
+	b := NewBag()
+
+	// Add occurrences from many goroutines, then remove them
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Add(j%10, 2)
+				b.Remove(j%10, 1)
+				_ = b.Union(b.Clone())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if b.Size() != 800 || b.Count(0) != 80 {
+		t.Fatalf("set.Bag - unexpected result: %d, %d", b.Size(), b.Count(0))
+	}
+}
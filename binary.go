@@ -0,0 +1,377 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// binaryVersion is the version of the binary encoding written by Set.MarshalBinary
+const binaryVersion = 1
+
+// Type tags which identify the kind of each element in the binary encoding of a Set.  These values
+// are part of the wire format, and must never be changed or reused.
+const (
+	tagNil     = 0
+	tagFalse   = 1
+	tagTrue    = 2
+	tagInt     = 3
+	tagInt8    = 4
+	tagInt16   = 5
+	tagInt32   = 6
+	tagInt64   = 7
+	tagUint    = 8
+	tagUint8   = 9
+	tagUint16  = 10
+	tagUint32  = 11
+	tagUint64  = 12
+	tagFloat32 = 13
+	tagFloat64 = 14
+	tagString  = 15
+	tagPair    = 16
+	tagSet     = 17
+	tagFrozen  = 18
+)
+
+// errBinaryCorrupt is returned when a binary encoded Set cannot be decoded
+var errBinaryCorrupt = errors.New("set: corrupt binary encoding")
+
+// maxNestingDepth is the deepest nesting of sets and pairs which can be decoded, so that hostile input
+// cannot exhaust the stack
+const maxNestingDepth = 10000
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the set using a compact, versioned
+// binary format.  The format is stable across releases, and is laid out as follows:
+//
+//	version  byte     format version, currently 1
+//	count    uvarint  number of elements
+//	elements ...      count encoded elements
+//
+// Each element begins with a single byte type tag, followed by a payload which depends on its type:
+//
+//	0       nil        no payload
+//	1, 2    bool       no payload, 1 is false and 2 is true
+//	3-7     int        int, int8, int16, int32 and int64, as a zig-zag encoded varint
+//	8-12    uint       uint, uint8, uint16, uint32 and uint64, as a uvarint
+//	13      float32    IEEE 754 bits, 4 bytes little endian
+//	14      float64    IEEE 754 bits, 8 bytes little endian
+//	15      string     uvarint length, followed by the bytes of the string
+//	16      Pair       encoded X element, followed by encoded Y element
+//	17      *Set       uvarint count, followed by count encoded elements
+//	18      FrozenSet  uvarint count, followed by count encoded elements
+//
+// Elements are written in ascending order of their encoded bytes, so that equal sets always have
+// the same encoding.  Elements of any other type cannot be encoded, and return an error.  Sets and pairs
+// nested more than 10000 deep cannot be decoded.
+func (s *Set) MarshalBinary() ([]byte, error) {
+	b := []byte{binaryVersion}
+	return appendBinarySet(b, s)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding a set which was encoded using
+// MarshalBinary, and replacing the contents of the current set
+func (s *Set) UnmarshalBinary(b []byte) error {
+	// Decode all elements into a new map
+	values, err := unmarshalBinaryValues(b)
+	if err != nil {
+		return err
+	}
+
+	m := make(map[interface{}]struct{}, len(values))
+	for _, v := range values {
+		m[v] = struct{}{}
+	}
+
+	// Lock set for write, and replace its contents
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replace(m)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder, using the same format as MarshalBinary
+func (s *Set) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, using the same format as UnmarshalBinary
+func (s *Set) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+// unmarshalBinaryValues checks the version of a binary encoded set, and decodes its elements
+func unmarshalBinaryValues(b []byte) ([]interface{}, error) {
+	// Check version
+	if len(b) == 0 {
+		return nil, errBinaryCorrupt
+	}
+	if b[0] != binaryVersion {
+		return nil, fmt.Errorf("set: unknown binary encoding version: %d", b[0])
+	}
+
+	// Decode all elements, which must consume the entire input
+	r := bytes.NewReader(b[1:])
+	values, err := readBinaryValues(r, maxNestingDepth)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errBinaryCorrupt
+	}
+
+	return values, nil
+}
+
+// appendBinarySet appends the count and sorted elements of a set to a buffer
+func appendBinarySet(b []byte, s *Set) ([]byte, error) {
+	return appendBinaryValues(b, s.Enumerate())
+}
+
+// appendBinaryValues appends the count and sorted elements of a slice of unique values to a buffer
+func appendBinaryValues(b []byte, values []interface{}) ([]byte, error) {
+	// Encode all elements
+	elements := make([][]byte, 0, len(values))
+	for _, v := range values {
+		e, err := appendBinaryElement(nil, v)
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, e)
+	}
+
+	// Sort elements by their encoded form
+	sort.Slice(elements, func(i int, j int) bool {
+		return bytes.Compare(elements[i], elements[j]) < 0
+	})
+
+	b = binary.AppendUvarint(b, uint64(len(elements)))
+	for _, e := range elements {
+		b = append(b, e...)
+	}
+
+	return b, nil
+}
+
+// appendBinaryElement appends the type tag and payload of a single element to a buffer
+func appendBinaryElement(b []byte, v interface{}) ([]byte, error) {
+	switch e := v.(type) {
+	case nil:
+		return append(b, tagNil), nil
+	case bool:
+		if e {
+			return append(b, tagTrue), nil
+		}
+		return append(b, tagFalse), nil
+	case int:
+		return binary.AppendVarint(append(b, tagInt), int64(e)), nil
+	case int8:
+		return binary.AppendVarint(append(b, tagInt8), int64(e)), nil
+	case int16:
+		return binary.AppendVarint(append(b, tagInt16), int64(e)), nil
+	case int32:
+		return binary.AppendVarint(append(b, tagInt32), int64(e)), nil
+	case int64:
+		return binary.AppendVarint(append(b, tagInt64), e), nil
+	case uint:
+		return binary.AppendUvarint(append(b, tagUint), uint64(e)), nil
+	case uint8:
+		return binary.AppendUvarint(append(b, tagUint8), uint64(e)), nil
+	case uint16:
+		return binary.AppendUvarint(append(b, tagUint16), uint64(e)), nil
+	case uint32:
+		return binary.AppendUvarint(append(b, tagUint32), uint64(e)), nil
+	case uint64:
+		return binary.AppendUvarint(append(b, tagUint64), e), nil
+	case float32:
+		return binary.LittleEndian.AppendUint32(append(b, tagFloat32), math.Float32bits(e)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(b, tagFloat64), math.Float64bits(e)), nil
+	case string:
+		b = binary.AppendUvarint(append(b, tagString), uint64(len(e)))
+		return append(b, e...), nil
+	case Pair:
+		b, err := appendBinaryElement(append(b, tagPair), e.X)
+		if err != nil {
+			return nil, err
+		}
+		return appendBinaryElement(b, e.Y)
+	case *Set:
+		return appendBinarySet(append(b, tagSet), e)
+	case FrozenSet:
+		return append(append(b, tagFrozen), e.canonical()...), nil
+	default:
+		return nil, fmt.Errorf("set: cannot binary encode element %v of type %T", v, v)
+	}
+}
+
+// readBinaryElements reads the count and elements of a set from a reader into a map, allowing elements
+// to nest up to the specified depth
+func readBinaryElements(r *bytes.Reader, depth int) (map[interface{}]struct{}, error) {
+	values, err := readBinaryValues(r, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[interface{}]struct{}, len(values))
+	for _, v := range values {
+		m[v] = struct{}{}
+	}
+
+	return m, nil
+}
+
+// readBinaryValues reads the count and elements of a set from a reader into a slice, allowing elements
+// to nest up to the specified depth
+func readBinaryValues(r *bytes.Reader, depth int) ([]interface{}, error) {
+	// Read the count, which cannot exceed the number of remaining bytes
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(r.Len()) {
+		return nil, errBinaryCorrupt
+	}
+
+	values := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		e, err := readBinaryElement(r, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, e)
+	}
+
+	return values, nil
+}
+
+// readBinaryElement reads the type tag and payload of a single element from a reader.  Nested sets and
+// pairs decrease the depth, and an element nested beyond it is corrupt.
+func readBinaryElement(r *bytes.Reader, depth int) (interface{}, error) {
+	if depth <= 0 {
+		return nil, errBinaryCorrupt
+	}
+
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, errBinaryCorrupt
+	}
+
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagInt, tagInt8, tagInt16, tagInt32, tagInt64:
+		i, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, errBinaryCorrupt
+		}
+
+		// Check that the value fits in its type
+		switch tag {
+		case tagInt:
+			if i >= math.MinInt && i <= math.MaxInt {
+				return int(i), nil
+			}
+		case tagInt8:
+			if i >= math.MinInt8 && i <= math.MaxInt8 {
+				return int8(i), nil
+			}
+		case tagInt16:
+			if i >= math.MinInt16 && i <= math.MaxInt16 {
+				return int16(i), nil
+			}
+		case tagInt32:
+			if i >= math.MinInt32 && i <= math.MaxInt32 {
+				return int32(i), nil
+			}
+		default:
+			return i, nil
+		}
+
+		return nil, errBinaryCorrupt
+	case tagUint, tagUint8, tagUint16, tagUint32, tagUint64:
+		u, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errBinaryCorrupt
+		}
+
+		// Check that the value fits in its type
+		switch tag {
+		case tagUint:
+			if u <= math.MaxUint {
+				return uint(u), nil
+			}
+		case tagUint8:
+			if u <= math.MaxUint8 {
+				return uint8(u), nil
+			}
+		case tagUint16:
+			if u <= math.MaxUint16 {
+				return uint16(u), nil
+			}
+		case tagUint32:
+			if u <= math.MaxUint32 {
+				return uint32(u), nil
+			}
+		default:
+			return u, nil
+		}
+
+		return nil, errBinaryCorrupt
+	case tagFloat32:
+		var bits uint32
+		if err := binary.Read(r, binary.LittleEndian, &bits); err != nil {
+			return nil, errBinaryCorrupt
+		}
+		return math.Float32frombits(bits), nil
+	case tagFloat64:
+		var bits uint64
+		if err := binary.Read(r, binary.LittleEndian, &bits); err != nil {
+			return nil, errBinaryCorrupt
+		}
+		return math.Float64frombits(bits), nil
+	case tagString:
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n > uint64(r.Len()) {
+			return nil, errBinaryCorrupt
+		}
+
+		str := make([]byte, n)
+		if _, err := r.Read(str); err != nil && n > 0 {
+			return nil, errBinaryCorrupt
+		}
+		return string(str), nil
+	case tagPair:
+		x, err := readBinaryElement(r, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		y, err := readBinaryElement(r, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		return Pair{X: x, Y: y}, nil
+	case tagSet:
+		m, err := readBinaryElements(r, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		return &Set{m: m}, nil
+	case tagFrozen:
+		values, err := readBinaryValues(r, depth-1)
+		if err != nil {
+			return nil, err
+		}
+
+		// Freeze the elements again, so that the set is canonical regardless of the input
+		return NewFrozen(values...)
+	default:
+		return nil, fmt.Errorf("set: unknown binary encoding type tag: %d", tag)
+	}
+}
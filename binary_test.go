@@ -0,0 +1,157 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"log"
+	"math"
+	"testing"
+)
+
+// TestMarshalBinary verifies that the set.MarshalBinary() and set.UnmarshalBinary() methods round
+// trip all supported element types
+func TestMarshalBinary(t *testing.T) {
+	log.Println("TestMarshalBinary()")
+
+	// Create a table of sets to encode
+	var tests = []*Set{
+		// Empty set
+		New(),
+		// Integers of every size
+		New(-1, int8(-2), int16(3), int32(-4), int64(math.MinInt64), uint(1), uint8(2), uint16(3), uint32(4), uint64(math.MaxUint64)),
+		// Floating point numbers, strings, booleans and nil
+		New(float32(1.5), 2.5, "", "hello", true, false, nil),
+		// Pairs
+		New(Pair{1, "a"}, Pair{Pair{1, 2}, nil}),
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		b, err := test.MarshalBinary()
+		if err != nil {
+			t.Fatalf("set.MarshalBinary() - unexpected error: %v", err)
+		}
+
+		out := New(42)
+		if err := out.UnmarshalBinary(b); err != nil {
+			t.Fatalf("set.UnmarshalBinary() - unexpected error: %v", err)
+		}
+		if !out.Equal(test) {
+			t.Fatalf("set.UnmarshalBinary() - sets not equal: %s != %s", out, test)
+		}
+
+		log.Println(test, "->", len(b), "bytes")
+	}
+}
+
+// TestMarshalBinaryFormat verifies that the set.MarshalBinary() method produces the documented,
+// deterministic wire format
+func TestMarshalBinaryFormat(t *testing.T) {
+	log.Println("TestMarshalBinaryFormat()")
+
+	// Create a table of tests and expected encodings
+	var tests = []struct {
+		source *Set
+		result []byte
+	}{
+		// Empty set
+		{New(), []byte{1, 0}},
+		// Integers, sorted by encoded form
+		{New(2, 1, -1), []byte{1, 3, tagInt, 1, tagInt, 2, tagInt, 4}},
+		// Strings and booleans
+		{New("ab", true), []byte{1, 2, tagTrue, tagString, 2, 'a', 'b'}},
+		// Pairs
+		{New(Pair{uint8(1), nil}), []byte{1, 1, tagPair, tagUint8, 1, tagNil}},
+		// Nested sets
+		{New(New(false)), []byte{1, 1, tagSet, 1, tagFalse}},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		b, err := test.source.MarshalBinary()
+		if err != nil {
+			t.Fatalf("set.MarshalBinary() - unexpected error: %v", err)
+		}
+		if !bytes.Equal(b, test.result) {
+			t.Fatalf("set.MarshalBinary() - unexpected result: %v != %v", b, test.result)
+		}
+	}
+
+	// Unsupported element types are rejected
+	if _, err := New(struct{}{}).MarshalBinary(); err == nil {
+		t.Fatalf("set.MarshalBinary() - expected error for unsupported type")
+	}
+
+	// Invalid input is rejected
+	for _, b := range [][]byte{nil, {2, 0}, {1, 1}, {1, 1, 99}, {1, 1, tagString, 5, 'a'}, {1, 1, tagInt8, 0x80, 0x04}, {1, 0, 0}} {
+		if err := New().UnmarshalBinary(b); err == nil {
+			t.Fatalf("set.UnmarshalBinary(%v) - expected error", b)
+		}
+	}
+}
+
+// TestUnmarshalBinaryDepth verifies that the set.UnmarshalBinary() method rejects deeply nested input,
+// rather than exhausting the stack
+func TestUnmarshalBinaryDepth(t *testing.T) {
+	log.Println("TestUnmarshalBinaryDepth()")
+
+	// nested encodes a set holding a single element, nested within the specified prefix n times, and
+	// followed by as many nil elements as the nesting requires
+	nested := func(prefix []byte, n int, nils int) []byte {
+		b := []byte{binaryVersion, 1}
+		b = append(b, bytes.Repeat(prefix, n)...)
+		return append(b, bytes.Repeat([]byte{tagNil}, nils)...)
+	}
+
+	// Create a table of tests and expected results
+	var tests = []struct {
+		name  string
+		input []byte
+		ok    bool
+	}{
+		{"sets within limit", nested([]byte{tagSet, 1}, 100, 1), true},
+		{"sets beyond limit", nested([]byte{tagSet, 1}, 1000000, 1), false},
+		{"frozen sets within limit", nested([]byte{tagFrozen, 1}, 100, 1), true},
+		{"frozen sets beyond limit", nested([]byte{tagFrozen, 1}, 1000000, 1), false},
+		{"pairs within limit", nested([]byte{tagPair}, 100, 101), true},
+		{"pairs beyond limit", nested([]byte{tagPair}, 1000000, 1000001), false},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if err := New().UnmarshalBinary(test.input); (err == nil) != test.ok {
+			t.Fatalf("set.UnmarshalBinary() - unexpected result for %s: %v", test.name, err)
+		}
+	}
+}
+
+// TestGob verifies that sets may be encoded and decoded using encoding/gob
+func TestGob(t *testing.T) {
+	log.Println("TestGob()")
+
+	// Create a struct which holds a set
+	type cache struct {
+		Name string
+		Seen *Set
+	}
+
+	in := cache{
+		Name: "seen",
+		Seen: New(1, "two", Pair{3, 4.0}),
+	}
+
+	// Encode and decode the struct
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("gob.Encode() - unexpected error: %v", err)
+	}
+
+	var out cache
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("gob.Decode() - unexpected error: %v", err)
+	}
+
+	if out.Name != in.Name || !out.Seen.Equal(in.Seen) {
+		t.Fatalf("gob.Decode() - unexpected result: %s != %s", out.Seen, in.Seen)
+	}
+}
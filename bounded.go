@@ -0,0 +1,424 @@
+package set
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+// EvictionPolicy determines which element a BoundedSet evicts when it is full
+type EvictionPolicy int
+
+const (
+	// EvictLRU evicts the least recently used element, where both Add and Has count as a use
+	EvictLRU EvictionPolicy = iota
+	// EvictLFU evicts the least frequently used element, where both Add and Has count as a use.  Ties
+	// are broken by evicting the least recently used element.
+	EvictLFU
+	// EvictFIFO evicts the element which was added earliest, regardless of use
+	EvictFIFO
+)
+
+// BoundedOptions configures the capacity and eviction behavior of a BoundedSet
+type BoundedOptions struct {
+	// Capacity is the maximum number of elements in the set, and must be greater than zero
+	Capacity int
+	// Policy determines which element is evicted when an element is added to a full set
+	Policy EvictionPolicy
+	// OnEvict, if not nil, is called once for each element which is evicted to make room for another.
+	// It is called without the set locked, so it may safely access the set.  It is not called for
+	// elements which are removed using Remove.
+	OnEvict func(value interface{})
+}
+
+// BoundedStats contains usage statistics for a BoundedSet
+type BoundedStats struct {
+	// Hits is the number of calls to Has which found the element
+	Hits uint64
+	// Misses is the number of calls to Has which did not find the element
+	Misses uint64
+	// Evictions is the number of elements evicted to make room for another
+	Evictions uint64
+}
+
+// BoundedSet represents an unordered collection of unique values, which holds no more than a fixed
+// number of elements.  When an element is added to a full set, another element is first evicted
+// according to the set's eviction policy.  A BoundedSet is safe for concurrent use.
+type BoundedSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Capacity and eviction options
+	opts BoundedOptions
+	// Entry for each element, holding its usage
+	m map[interface{}]*boundedEntry
+	// Min-heap of entries, ordered so that the next entry to be evicted is first
+	entries boundedHeap
+	// Counter used to order uses of elements
+	tick uint64
+	// Usage statistics
+	stats BoundedStats
+}
+
+// boundedEntry holds the usage of a single element in a BoundedSet
+type boundedEntry struct {
+	// Element of the set
+	value interface{}
+	// Tick of the most recent use of the element, or of its insertion for EvictFIFO
+	tick uint64
+	// Number of uses of the element
+	uses uint64
+	// Index of the entry within the heap
+	index int
+}
+
+// NewBounded creates a new BoundedSet using the specified options, optionally adding initial elements
+// to the set.  NewBounded panics if the capacity is not greater than zero.
+func NewBounded(opts BoundedOptions, values ...interface{}) *BoundedSet {
+	if opts.Capacity <= 0 {
+		panic("set: bounded set capacity must be greater than zero")
+	}
+
+	// Initialize set
+	s := BoundedSet{
+		opts: opts,
+		m:    make(map[interface{}]*boundedEntry, min(len(values), opts.Capacity)),
+	}
+	s.entries.policy = opts.Policy
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  If the element already existed, it counts as a use of the element.  If the
+// set is full, an element is evicted to make room for the new element.
+func (s *BoundedSet) Add(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+
+	// Check existence, and record a use of the element
+	if e, ok := s.m[value]; ok {
+		s.touch(e)
+		s.mutex.Unlock()
+		return false
+	}
+
+	// Evict an element if the set is full
+	var evicted interface{}
+	full := len(s.m) >= s.opts.Capacity
+	if full {
+		e := heap.Pop(&s.entries).(*boundedEntry)
+		delete(s.m, e.value)
+		evicted = e.value
+		s.stats.Evictions++
+	}
+
+	// Add value to set
+	s.tick++
+	e := &boundedEntry{
+		value: value,
+		tick:  s.tick,
+		uses:  1,
+	}
+	s.m[value] = e
+	heap.Push(&s.entries, e)
+
+	s.mutex.Unlock()
+
+	// Notify of eviction without the lock held
+	if full && s.opts.OnEvict != nil {
+		s.opts.OnEvict(evicted)
+	}
+
+	return true
+}
+
+// Capacity returns the maximum number of elements in the set
+func (s *BoundedSet) Capacity() int {
+	return s.opts.Capacity
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set.  The new set has the same capacity and policy as this set, and
+// elements retain their usage from this set.
+func (s *BoundedSet) Difference(t *BoundedSet) *BoundedSet {
+	return s.combine(t, func(ok bool) bool {
+		return !ok
+	}, false)
+}
+
+// Enumerate returns a slice of all elements in the set, in order of eviction, so that the element
+// which would be evicted next is first
+func (s *BoundedSet) Enumerate() []interface{} {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice, and order them by eviction
+	entries := s.sorted()
+	values := make([]interface{}, len(entries))
+	for i, e := range entries {
+		values[i] = e.value
+	}
+
+	return values
+}
+
+// Has checks for membership of an element in the set.  If the element is a member, it counts as a
+// use of the element, and as a hit, otherwise it counts as a miss.
+func (s *BoundedSet) Has(value interface{}) bool {
+	// Lock set for write, as usage is recorded
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e, ok := s.m[value]
+	if !ok {
+		s.stats.Misses++
+		return false
+	}
+
+	s.stats.Hits++
+	s.touch(e)
+	return true
+}
+
+// Intersection returns a set containing all elements present in both the current set and the
+// parameter set.  The new set has the same capacity and policy as this set, and elements retain their
+// usage from this set.
+func (s *BoundedSet) Intersection(t *BoundedSet) *BoundedSet {
+	return s.combine(t, func(ok bool) bool {
+		return ok
+	}, false)
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it
+// did not exist.  No OnEvict callback is made for the removed element.
+func (s *BoundedSet) Remove(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence, remove value from set
+	e, ok := s.m[value]
+	if !ok {
+		return false
+	}
+
+	heap.Remove(&s.entries, e.index)
+	delete(s.m, value)
+	return true
+}
+
+// Size returns the size or cardinality of this set
+func (s *BoundedSet) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.m)
+}
+
+// Stats returns the usage statistics of this set
+func (s *BoundedSet) Stats() BoundedStats {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.stats
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *BoundedSet) String() string {
+	return s.ToSet().String()
+}
+
+// ToSet copies the elements of the current set into a new Set, without their usage
+func (s *BoundedSet) ToSet() *Set {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy set into a new set
+	outSet := &Set{
+		m: make(map[interface{}]struct{}, len(s.m)),
+	}
+	for k := range s.m {
+		outSet.m[k] = struct{}{}
+	}
+
+	return outSet
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set.  The new set has the same capacity and policy as this set.  Elements retain
+// their usage from this set, or from the parameter set if only present there.
+//
+// The usage of elements in two sets is recorded by unrelated clocks, so if the union exceeds the
+// capacity, elements are compared by their relative position in the eviction order of their own set,
+// and those nearest to eviction are discarded.  When elements of both sets are equally near to
+// eviction, the element of the parameter set is discarded first.  Neither set is modified, so
+// discarded elements are not reported to OnEvict, and do not count as evictions.
+func (s *BoundedSet) Union(t *BoundedSet) *BoundedSet {
+	return s.combine(t, func(bool) bool {
+		return true
+	}, true)
+}
+
+// combine creates a new set with the same options as this set, but without an OnEvict callback.
+// Each entry of this set is kept if the keep function returns true, given whether or not the element
+// is present in the parameter set.  If all is true, entries only present in the parameter set are
+// also kept.  Entries are then ordered by eviction, and those beyond the capacity are discarded.
+func (s *BoundedSet) combine(t *BoundedSet, keep func(bool) bool, all bool) *BoundedSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Gather entries to keep, ranked by their relative position in the eviction order of their own
+	// set, as the ticks of two sets cannot be compared
+	var entries []rankedEntry
+	sorted := s.sorted()
+	for i, e := range sorted {
+		if _, ok := t.m[e.value]; keep(ok) {
+			entries = append(entries, rankedEntry{e, float64(i+1) / float64(len(sorted)), true})
+		}
+	}
+	if all {
+		sorted := t.sorted()
+		for i, e := range sorted {
+			if _, ok := s.m[e.value]; !ok {
+				entries = append(entries, rankedEntry{e, float64(i+1) / float64(len(sorted)), false})
+			}
+		}
+	}
+
+	// Order entries by rank, placing entries of the parameter set first when equally ranked
+	sort.SliceStable(entries, func(i int, j int) bool {
+		if entries[i].rank != entries[j].rank {
+			return entries[i].rank < entries[j].rank
+		}
+
+		return !entries[i].own && entries[j].own
+	})
+
+	// Copy entries into a new set, assigning ticks in order of rank, and preserving their uses
+	out := &BoundedSet{
+		opts: BoundedOptions{
+			Capacity: s.opts.Capacity,
+			Policy:   s.opts.Policy,
+		},
+	}
+	out.entries.policy = s.opts.Policy
+	copies := make([]*boundedEntry, len(entries))
+	for i, r := range entries {
+		out.tick++
+		copies[i] = &boundedEntry{
+			value: r.value,
+			tick:  out.tick,
+			uses:  r.uses,
+		}
+	}
+
+	// Order copies by eviction, keeping only those which fit
+	sort.SliceStable(copies, func(i int, j int) bool {
+		return s.opts.Policy.before(copies[i], copies[j])
+	})
+	if len(copies) > s.opts.Capacity {
+		copies = copies[len(copies)-s.opts.Capacity:]
+	}
+
+	out.m = make(map[interface{}]*boundedEntry, len(copies))
+	for _, c := range copies {
+		out.m[c.value] = c
+		heap.Push(&out.entries, c)
+	}
+
+	return out
+}
+
+// rankedEntry is an entry of a BoundedSet, ranked by its position in the eviction order of its set,
+// from just above zero for the next entry to be evicted, to one for the last
+type rankedEntry struct {
+	*boundedEntry
+	// Relative position of the entry in the eviction order of its set
+	rank float64
+	// Whether or not the entry belongs to the receiver of the operation
+	own bool
+}
+
+// before returns whether or not entry a should be evicted before entry b under this policy
+func (p EvictionPolicy) before(a *boundedEntry, b *boundedEntry) bool {
+	if p == EvictLFU && a.uses != b.uses {
+		return a.uses < b.uses
+	}
+
+	return a.tick < b.tick
+}
+
+// sorted returns all entries of the set in order of eviction.  The caller must hold the lock.
+func (s *BoundedSet) sorted() []*boundedEntry {
+	entries := make([]*boundedEntry, len(s.entries.entries))
+	copy(entries, s.entries.entries)
+
+	// Sorting a copy leaves the indices of the entries unchanged
+	sort.Slice(entries, func(i int, j int) bool {
+		return s.opts.Policy.before(entries[i], entries[j])
+	})
+
+	return entries
+}
+
+// touch records a use of an element.  The caller must hold the lock for write.
+func (s *BoundedSet) touch(e *boundedEntry) {
+	e.uses++
+	if s.opts.Policy != EvictFIFO {
+		s.tick++
+		e.tick = s.tick
+	}
+
+	heap.Fix(&s.entries, e.index)
+}
+
+// boundedHeap is a min-heap of entries ordered by eviction policy, implementing heap.Interface
+type boundedHeap struct {
+	// Entries in heap order
+	entries []*boundedEntry
+	// Policy which determines the order of entries
+	policy EvictionPolicy
+}
+
+// Len returns the number of entries in the heap
+func (h *boundedHeap) Len() int {
+	return len(h.entries)
+}
+
+// Less orders entries so that the entry which should be evicted first is least
+func (h *boundedHeap) Less(i int, j int) bool {
+	return h.policy.before(h.entries[i], h.entries[j])
+}
+
+// Swap exchanges two entries, updating their indices
+func (h *boundedHeap) Swap(i int, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].index = i
+	h.entries[j].index = j
+}
+
+// Push adds an entry to the end of the heap
+func (h *boundedHeap) Push(x interface{}) {
+	e := x.(*boundedEntry)
+	e.index = len(h.entries)
+	h.entries = append(h.entries, e)
+}
+
+// Pop removes the entry at the end of the heap
+func (h *boundedHeap) Pop() interface{} {
+	old := h.entries
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	h.entries = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,183 @@
+package set
+
+import (
+	"log"
+	"sync"
+	"testing"
+)
+
+// TestBoundedEviction verifies that each eviction policy of a BoundedSet evicts the correct elements
+func TestBoundedEviction(t *testing.T) {
+	log.Println("TestBoundedEviction()")
+
+	// Create a table of policies and expected results after the same sequence of operations
+	var tests = []struct {
+		policy  EvictionPolicy
+		members *Set
+		evicted []interface{}
+	}{
+		// 3 was least recently used, then 1
+		{EvictLRU, New(2, 4, 5), []interface{}{3, 1}},
+		// 3 was used least, then 4 was used less recently than 2 and 5
+		{EvictLFU, New(1, 2, 5), []interface{}{3, 4}},
+		// 1 and 2 were added earliest
+		{EvictFIFO, New(3, 4, 5), []interface{}{1, 2}},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		var evicted []interface{}
+		s := NewBounded(BoundedOptions{
+			Capacity: 3,
+			Policy:   test.policy,
+			OnEvict: func(v interface{}) {
+				evicted = append(evicted, v)
+			},
+		}, 1, 2, 3)
+
+		// Use 1 twice and 2 once, then fill the set
+		s.Has(1)
+		s.Add(1)
+		s.Has(2)
+		s.Add(4)
+		s.Has(2)
+		s.Add(5)
+
+		if !s.ToSet().Equal(test.members) {
+			t.Fatalf("set.BoundedSet(%d) - unexpected members: %s != %s", test.policy, s, test.members)
+		}
+		if len(evicted) != len(test.evicted) || evicted[0] != test.evicted[0] || evicted[1] != test.evicted[1] {
+			t.Fatalf("set.BoundedSet(%d) - unexpected evictions: %v != %v", test.policy, evicted, test.evicted)
+		}
+		if s.Size() != s.Capacity() {
+			t.Fatalf("set.BoundedSet(%d) - unexpected size: %d", test.policy, s.Size())
+		}
+	}
+}
+
+// TestBoundedStats verifies that the boundedset.Stats() method is working properly
+func TestBoundedStats(t *testing.T) {
+	log.Println("TestBoundedStats()")
+
+	s := NewBounded(BoundedOptions{Capacity: 2}, "a", "b")
+	s.Has("a")
+	s.Has("a")
+	s.Has("c")
+	s.Add("c")
+	s.Add("d")
+
+	stats := s.Stats()
+	if stats != (BoundedStats{Hits: 2, Misses: 1, Evictions: 2}) {
+		t.Fatalf("set.BoundedSet.Stats() - unexpected result: %+v", stats)
+	}
+
+	// Removal is not an eviction
+	if !s.Remove("c") || s.Remove("c") || s.Stats().Evictions != 2 {
+		t.Fatalf("set.BoundedSet.Remove() - unexpected result")
+	}
+	if s.Add("e"); s.Stats().Evictions != 2 || !s.ToSet().Equal(New("d", "e")) {
+		t.Fatalf("set.BoundedSet.Add() - unexpected result: %s", s)
+	}
+}
+
+// TestBoundedEnumerate verifies that the boundedset.Enumerate() method returns elements in order of eviction
+func TestBoundedEnumerate(t *testing.T) {
+	log.Println("TestBoundedEnumerate()")
+
+	s := NewBounded(BoundedOptions{Capacity: 4}, 1, 2, 3, 4)
+	s.Has(2)
+	s.Has(1)
+
+	values := s.Enumerate()
+	for i, v := range []interface{}{3, 4, 2, 1} {
+		if values[i] != v {
+			t.Fatalf("set.BoundedSet.Enumerate() - unexpected result: %v", values)
+		}
+	}
+}
+
+// TestBoundedAlgebra verifies that the boundedset.Union(), boundedset.Intersection() and
+// boundedset.Difference() methods are working properly
+func TestBoundedAlgebra(t *testing.T) {
+	log.Println("TestBoundedAlgebra()")
+
+	x := NewBounded(BoundedOptions{Capacity: 4}, 1, 2, 3)
+	y := NewBounded(BoundedOptions{Capacity: 10}, 3, 4, 5)
+
+	// Create a table of tests and expected results
+	var tests = []struct {
+		name   string
+		result *BoundedSet
+		target *Set
+	}{
+		{"Intersection", x.Intersection(y), New(3)},
+		{"Difference", x.Difference(y), New(1, 2)},
+		{"Difference", y.Difference(x), New(4, 5)},
+		// The union exceeds the capacity of x, so its least recently used element is discarded
+		{"Union", x.Union(y), New(2, 3, 4, 5)},
+		{"Union", y.Union(x), New(1, 2, 3, 4, 5)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.ToSet().Equal(test.target) {
+			t.Fatalf("set.BoundedSet.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+	}
+
+	// Source sets are unchanged, and still evict correctly
+	if x.Add(6); !x.ToSet().Equal(New(1, 2, 3, 6)) {
+		t.Fatalf("set.BoundedSet.Add() - unexpected result: %s", x)
+	}
+	if x.Add(7); !x.ToSet().Equal(New(2, 3, 6, 7)) {
+		t.Fatalf("set.BoundedSet.Add() - unexpected result: %s", x)
+	}
+
+	// Elements are ranked within their own set, so a set which has been used more often does not
+	// crowd out the other when the union exceeds the capacity
+	busy := NewBounded(BoundedOptions{Capacity: 3}, "a", "b", "c")
+	for i := 0; i < 50; i++ {
+		busy.Has("a")
+		busy.Has("b")
+		busy.Has("c")
+	}
+	idle := NewBounded(BoundedOptions{Capacity: 3}, "d", "e", "f")
+
+	// When equally ranked, elements of the parameter set are discarded first
+	if u := busy.Union(idle); !u.ToSet().Equal(New("b", "c", "f")) {
+		t.Fatalf("set.BoundedSet.Union() - unexpected result: %s", u)
+	}
+	if u := idle.Union(busy); !u.ToSet().Equal(New("c", "e", "f")) {
+		t.Fatalf("set.BoundedSet.Union() - unexpected result: %s", u)
+	}
+
+	// Neither set records the discarded elements as evictions
+	if stats := busy.Stats(); stats.Evictions != 0 {
+		t.Fatalf("set.BoundedSet.Stats() - unexpected evictions: %d", stats.Evictions)
+	}
+}
+
+// TestBoundedConcurrent verifies that bounded sets are safe for concurrent use
+func TestBoundedConcurrent(t *testing.T) {
+	log.Println("TestBoundedConcurrent()")
+
+	s := NewBounded(BoundedOptions{Capacity: 16, Policy: EvictLFU})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				s.Add(i*1000 + j)
+				s.Has(j)
+				_ = s.Union(s)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Size() != 16 {
+		t.Fatalf("set.BoundedSet.Size() - unexpected result: %d", s.Size())
+	}
+}
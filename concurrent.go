@@ -0,0 +1,312 @@
+package set
+
+import (
+	"hash/maphash"
+	"sync"
+	"unsafe"
+)
+
+// DefaultShards is the number of shards used by a ConcurrentSet created with NewConcurrent
+const DefaultShards = 32
+
+// ConcurrentSet represents an unordered collection of unique values, which is split into a number of
+// independently locked shards.  Operations on elements which hash to different shards do not contend
+// with each other, making ConcurrentSet well suited to write-heavy workloads with many goroutines.
+//
+// Operations which span the entire set, such as Size and Enumerate, lock each shard in turn, and so
+// do not provide a consistent snapshot of the set while it is being concurrently modified.
+type ConcurrentSet struct {
+	// Seed used to hash elements to shards
+	seed maphash.Seed
+	// Mask applied to element hashes to select a shard
+	mask uint64
+	// Independently locked shards of the set
+	shards []concurrentShard
+}
+
+// concurrentShard is a single, independently locked shard of a ConcurrentSet
+type concurrentShard struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Empty struct consumes no memory, so we just use the map keys
+	m map[interface{}]struct{}
+	// Padding to round the size of a shard up to a multiple of the 64 byte cache line size, so that
+	// neighbouring shards never share a cache line
+	_ [(64 - (unsafe.Sizeof(sync.RWMutex{})+unsafe.Sizeof(map[interface{}]struct{}(nil)))%64) % 64]byte
+}
+
+// NewConcurrent creates a new ConcurrentSet with DefaultShards shards, optionally adding initial
+// elements to the set
+func NewConcurrent(values ...interface{}) *ConcurrentSet {
+	return NewConcurrentShards(DefaultShards, values...)
+}
+
+// NewConcurrentShards creates a new ConcurrentSet with the specified number of shards, optionally
+// adding initial elements to the set.  The number of shards is rounded up to a power of two.
+func NewConcurrentShards(shards int, values ...interface{}) *ConcurrentSet {
+	// Round the number of shards up to a power of two
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+
+	// Initialize set
+	s := ConcurrentSet{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(n - 1),
+		shards: make([]concurrentShard, n),
+	}
+	for i := range s.shards {
+		s.shards[i].m = make(map[interface{}]struct{})
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed
+func (s *ConcurrentSet) Add(value interface{}) bool {
+	// Lock shard for write
+	sh := s.shard(value)
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	// Check existence, add value to set
+	if _, ok := sh.m[value]; ok {
+		return false
+	}
+
+	sh.m[value] = struct{}{}
+	return true
+}
+
+// CartesianProduct returns a set containing ordered pairs of every permutation between two sets
+func (s *ConcurrentSet) CartesianProduct(t *ConcurrentSet) *ConcurrentSet {
+	// Create a set of ordered pair permutations between the sets
+	cpSet := s.empty()
+
+	// Enumerate the source set
+	for _, x := range s.Enumerate() {
+		// Enumerate the target set
+		for _, y := range t.Enumerate() {
+			// Create pair, insert elements, insert into set
+			cpSet.Add(Pair{
+				X: x,
+				Y: y,
+			})
+		}
+	}
+
+	return cpSet
+}
+
+// Clone copies the current set into a new, identical set with the same number of shards
+func (s *ConcurrentSet) Clone() *ConcurrentSet {
+	// Copy set into a new set
+	outSet := s.empty()
+	for _, v := range s.Enumerate() {
+		outSet.Add(v)
+	}
+
+	return outSet
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *ConcurrentSet) Difference(t *ConcurrentSet) *ConcurrentSet {
+	return s.Filter(func(v interface{}) bool {
+		return !t.Has(v)
+	})
+}
+
+// Enumerate returns an unordered slice of all elements in the set
+func (s *ConcurrentSet) Enumerate() []interface{} {
+	// Gather all values into a slice
+	values := make([]interface{}, 0)
+	for i := range s.shards {
+		// Lock shard for read
+		sh := &s.shards[i]
+		sh.mutex.RLock()
+		for k := range sh.m {
+			values = append(values, k)
+		}
+		sh.mutex.RUnlock()
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets have the same length and no differences, meaning they are equal
+func (s *ConcurrentSet) Equal(t *ConcurrentSet) bool {
+	return s.Size() == t.Size() && s.Difference(t).Size() == 0
+}
+
+// Filter applies a function over all elements of the set, and returns all elements which return true
+// when the function is applied
+func (s *ConcurrentSet) Filter(fn func(interface{}) bool) *ConcurrentSet {
+	// Create a set to return with elements which match filter function
+	filterSet := s.empty()
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, add elements which it matches
+		if fn(e) {
+			filterSet.Add(e)
+		}
+	}
+
+	return filterSet
+}
+
+// Has checks for membership of an element in the set
+func (s *ConcurrentSet) Has(value interface{}) bool {
+	// Lock shard for read
+	sh := s.shard(value)
+	sh.mutex.RLock()
+	defer sh.mutex.RUnlock()
+
+	_, ok := sh.m[value]
+	return ok
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *ConcurrentSet) Intersection(t *ConcurrentSet) *ConcurrentSet {
+	return s.Filter(t.Has)
+}
+
+// Map applies a function over all elements of the set, and returns the resulting set
+func (s *ConcurrentSet) Map(fn func(interface{}) interface{}) *ConcurrentSet {
+	// Create a set to return with function applied
+	mapSet := s.empty()
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, capture result
+		mapSet.Add(fn(e))
+	}
+
+	return mapSet
+}
+
+// PowerSet generates a set of all possible subsets, given the current set
+func (s *ConcurrentSet) PowerSet() *ConcurrentSet {
+	// Start with the empty set
+	subsets := []*ConcurrentSet{s.empty()}
+
+	// For each element, add a copy of every existing subset with the element added to it
+	for _, e := range s.Enumerate() {
+		for _, p := range subsets {
+			hSet := p.Clone()
+			hSet.Add(e)
+			subsets = append(subsets, hSet)
+		}
+	}
+
+	// Gather all subsets into a set
+	pSet := s.empty()
+	for _, p := range subsets {
+		pSet.Add(p)
+	}
+
+	return pSet
+}
+
+// Reduce applies a function over all elements of the set, accumulating the results into a final result value
+func (s *ConcurrentSet) Reduce(value interface{}, fn func(interface{}, interface{}) interface{}) interface{} {
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		value = fn(value, e)
+	}
+
+	return value
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *ConcurrentSet) Remove(value interface{}) bool {
+	// Lock shard for write
+	sh := s.shard(value)
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	// Check existence, remove value from set
+	if _, ok := sh.m[value]; !ok {
+		return false
+	}
+
+	delete(sh.m, value)
+	return true
+}
+
+// Size returns the size or cardinality of this set
+func (s *ConcurrentSet) Size() int {
+	// Sum the size of all shards
+	size := 0
+	for i := range s.shards {
+		// Lock shard for read
+		sh := &s.shards[i]
+		sh.mutex.RLock()
+		size += len(sh.m)
+		sh.mutex.RUnlock()
+	}
+
+	return size
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *ConcurrentSet) String() string {
+	return formatValues(s.Enumerate())
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *ConcurrentSet) Subset(t *ConcurrentSet) bool {
+	// Check if all elements in the parameter set are contained within the set
+	for _, v := range t.Enumerate() {
+		// Check if element is contained, if not, return false
+		if !s.Has(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *ConcurrentSet) SymmetricDifference(t *ConcurrentSet) *ConcurrentSet {
+	return s.Difference(t).Union(t.Difference(s))
+}
+
+// ToSet copies the elements of the current set into a new Set
+func (s *ConcurrentSet) ToSet() *Set {
+	return New(s.Enumerate()...)
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *ConcurrentSet) Union(t *ConcurrentSet) *ConcurrentSet {
+	// Clone the current set into a new set
+	outSet := s.Clone()
+
+	// Enumerate and add all elements from the parameter set
+	for _, e := range t.Enumerate() {
+		outSet.Add(e)
+	}
+
+	return outSet
+}
+
+// empty creates a new, empty set with the same number of shards as the current set
+func (s *ConcurrentSet) empty() *ConcurrentSet {
+	return NewConcurrentShards(len(s.shards))
+}
+
+// shard returns the shard which is responsible for storing a value
+func (s *ConcurrentSet) shard(value interface{}) *concurrentShard {
+	return &s.shards[maphash.Comparable(s.seed, value)&s.mask]
+}
@@ -0,0 +1,61 @@
+package set
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// contentionGoroutines is the number of goroutines used by each contention benchmark
+var contentionGoroutines = []int{1, 2, 4, 8, 16, 32, 64}
+
+// contentionSet is the set of methods exercised by the contention benchmarks, which is implemented
+// by both Set and ConcurrentSet
+type contentionSet interface {
+	Add(interface{}) bool
+	Has(interface{}) bool
+	Remove(interface{}) bool
+}
+
+// benchmarkContention checks the performance of a mixed Add, Has and Remove workload, split across
+// a number of goroutines
+func benchmarkContention(b *testing.B, newSet func() contentionSet) {
+	for _, g := range contentionGoroutines {
+		b.Run(fmt.Sprintf("goroutines-%d", g), func(b *testing.B) {
+			// Create a new set
+			set := newSet()
+
+			b.ResetTimer()
+
+			// Split b.N operations across g goroutines, each working on its own range of elements
+			var wg sync.WaitGroup
+			for i := 0; i < g; i++ {
+				wg.Add(1)
+				go func(offset int) {
+					defer wg.Done()
+
+					for j := offset; j < b.N; j += g {
+						set.Add(j)
+						set.Has(j)
+						set.Remove(j)
+					}
+				}(i)
+			}
+			wg.Wait()
+		})
+	}
+}
+
+// BenchmarkContentionSet checks the performance of the mutex-based Set under contention
+func BenchmarkContentionSet(b *testing.B) {
+	benchmarkContention(b, func() contentionSet {
+		return New()
+	})
+}
+
+// BenchmarkContentionConcurrentSet checks the performance of the sharded ConcurrentSet under contention
+func BenchmarkContentionConcurrentSet(b *testing.B) {
+	benchmarkContention(b, func() contentionSet {
+		return NewConcurrent()
+	})
+}
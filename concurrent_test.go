@@ -0,0 +1,135 @@
+package set
+
+import (
+	"log"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// TestConcurrentAddRemove verifies that the ConcurrentSet.Add() and ConcurrentSet.Remove() methods
+// report exactly one winner for each element when used from many goroutines
+func TestConcurrentAddRemove(t *testing.T) {
+	log.Println("TestConcurrentAddRemove()")
+
+	// Create a set
+	set := NewConcurrent()
+
+	const goroutines = 16
+	const elements = 1000
+
+	// run calls fn for every element from many goroutines, counting the number of calls which
+	// returned true
+	run := func(fn func(interface{}) bool) int {
+		var mu sync.Mutex
+		count := 0
+
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				n := 0
+				for j := 0; j < elements; j++ {
+					if fn(j) {
+						n++
+					}
+				}
+
+				mu.Lock()
+				count += n
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		return count
+	}
+
+	// Have every goroutine attempt to add, then remove, the same elements
+	added := run(set.Add)
+	if set.Size() != elements {
+		t.Fatalf("set.Size() - unexpected result: %d", set.Size())
+	}
+	removed := run(set.Remove)
+
+	// Each element should have been added once, and removed once
+	if added != elements || removed != elements {
+		t.Fatalf("set.Add() and set.Remove() - unexpected results: %d added, %d removed", added, removed)
+	}
+	if set.Size() != 0 {
+		t.Fatalf("set.Size() - unexpected result: %d", set.Size())
+	}
+}
+
+// TestConcurrentAlgebra verifies that the ConcurrentSet set algebra methods are working properly
+func TestConcurrentAlgebra(t *testing.T) {
+	log.Println("TestConcurrentAlgebra()")
+
+	// Create a set with few shards, add some initial values
+	set := NewConcurrentShards(3, 1, 3, 5)
+	other := NewConcurrent(1, 2, 6)
+
+	// Create a table of tests and expected results of set algebra
+	var tests = []struct {
+		name   string
+		result *ConcurrentSet
+		target *ConcurrentSet
+	}{
+		{"Union", set.Union(other), NewConcurrent(1, 2, 3, 5, 6)},
+		{"Intersection", set.Intersection(other), NewConcurrent(1)},
+		{"Difference", set.Difference(other), NewConcurrent(3, 5)},
+		{"SymmetricDifference", set.SymmetricDifference(other), NewConcurrent(2, 3, 5, 6)},
+		{"CartesianProduct", NewConcurrent(1, 2).CartesianProduct(NewConcurrent(3)), NewConcurrent(Pair{1, 3}, Pair{2, 3})},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+
+		log.Println(test.name, "(", set, ",", other, ") =", test.result)
+	}
+
+	// Verify subsets, power sets and conversion
+	if !set.Subset(NewConcurrent(1, 5)) || set.Subset(other) {
+		t.Fatalf("set.Subset() - unexpected result")
+	}
+	if size := set.PowerSet().Size(); size != 8 {
+		t.Fatalf("set.PowerSet() - unexpected size: %d", size)
+	}
+	if !set.ToSet().Equal(New(1, 3, 5)) {
+		t.Fatalf("set.ToSet() - unexpected result: %s", set.ToSet())
+	}
+}
+
+// TestConcurrentShardSize verifies that shards are padded to the next multiple of the cache line size, so that
+// neighbouring shards never share a cache line
+func TestConcurrentShardSize(t *testing.T) {
+	log.Println("TestConcurrentShardSize()")
+
+	if size := unsafe.Sizeof(concurrentShard{}); size%64 != 0 {
+		t.Fatalf("set.concurrentShard - size is not a multiple of 64: %d", size)
+	}
+
+	// Padding never adds a whole cache line
+	unpadded := unsafe.Sizeof(sync.RWMutex{}) + unsafe.Sizeof(map[interface{}]struct{}(nil))
+	if size := unsafe.Sizeof(concurrentShard{}); size-unpadded >= 64 {
+		t.Fatalf("set.concurrentShard - unexpected padding: %d bytes", size-unpadded)
+	}
+}
+
+// TestConcurrentString verifies that the ConcurrentSet.String() method prints elements in canonical
+// sorted order
+func TestConcurrentString(t *testing.T) {
+	log.Println("TestConcurrentString()")
+
+	if s := NewConcurrent().String(); s != "{ Ø }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+	if s := NewConcurrent(3, "b", 1, Pair{1, 2}, 2, "a").String(); s != "{ 1 2 3 (1, 2) a b }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+}
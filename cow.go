@@ -0,0 +1,166 @@
+package set
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// COWSet represents an unordered collection of unique values, optimized for sets which are read far
+// more often than they are written.  The contents of the set are held in an immutable PersistentSet,
+// published through an atomic pointer: readers load the current version without taking any lock, and
+// writers build a new version, sharing structure with the old one, and publish it.
+//
+// Readers never block writers, and writers never block readers, although writers are serialized with
+// respect to each other.  Use Snapshot to obtain a frozen, consistent view of the set, which is
+// unaffected by later writes.  A COWSet is safe for concurrent use, and its zero value is an empty set.
+type COWSet struct {
+	// Mutex to serialize writers; readers never take it
+	mutex sync.Mutex
+	// Current version of the set
+	current atomic.Pointer[PersistentSet]
+}
+
+// NewCOW creates a new COWSet, optionally adding initial elements to the set
+func NewCOW(values ...interface{}) *COWSet {
+	// Initialize set
+	s := COWSet{}
+	s.current.Store(NewPersistent(values...))
+
+	return &s
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed
+func (s *COWSet) Add(value interface{}) bool {
+	return s.AddAll(value)[0]
+}
+
+// AddAll inserts all elements into the set, publishing a single new version, and returns a slice which
+// reports, for each element, whether or not it was newly added
+func (s *COWSet) AddAll(values ...interface{}) []bool {
+	return s.update(values, (*PersistentSet).With)
+}
+
+// All returns an iterator over all elements of the current version of the set, in no particular order,
+// for use with a range loop.  Iteration does not block writers, and does not observe their changes.
+func (s *COWSet) All() iter.Seq[interface{}] {
+	return s.Snapshot().All()
+}
+
+// Clone copies the current set into a new, identical set, without copying any elements
+func (s *COWSet) Clone() *COWSet {
+	return cowFrom(s.Snapshot())
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *COWSet) Difference(t *COWSet) *COWSet {
+	return cowFrom(s.Snapshot().Difference(t.Snapshot()))
+}
+
+// Enumerate returns an unordered slice of all elements in the current version of the set, without
+// blocking writers
+func (s *COWSet) Enumerate() []interface{} {
+	return s.Snapshot().Enumerate()
+}
+
+// Equal returns whether or not two sets contain exactly the same elements
+func (s *COWSet) Equal(t *COWSet) bool {
+	return s.Snapshot().Equal(t.Snapshot())
+}
+
+// Has checks for membership of an element in the set, without taking any lock
+func (s *COWSet) Has(value interface{}) bool {
+	return s.Snapshot().Has(value)
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *COWSet) Intersection(t *COWSet) *COWSet {
+	return cowFrom(s.Snapshot().Intersection(t.Snapshot()))
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *COWSet) Remove(value interface{}) bool {
+	return s.RemoveAll(value)[0]
+}
+
+// RemoveAll destroys all elements in the set, publishing a single new version, and returns a slice which
+// reports, for each element, whether or not it was destroyed
+func (s *COWSet) RemoveAll(values ...interface{}) []bool {
+	return s.update(values, (*PersistentSet).Without)
+}
+
+// Size returns the size or cardinality of this set
+func (s *COWSet) Size() int {
+	return s.Snapshot().Size()
+}
+
+// Snapshot returns the current version of the set.  The returned set is immutable, so it remains a
+// consistent view of the set at the time of the call, regardless of later writes.
+func (s *COWSet) Snapshot() *PersistentSet {
+	// No version has been published yet
+	p := s.current.Load()
+	if p == nil {
+		return &PersistentSet{}
+	}
+
+	return p
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *COWSet) String() string {
+	return s.Snapshot().String()
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *COWSet) Subset(t *COWSet) bool {
+	return s.Snapshot().Subset(t.Snapshot())
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *COWSet) SymmetricDifference(t *COWSet) *COWSet {
+	return cowFrom(s.Snapshot().SymmetricDifference(t.Snapshot()))
+}
+
+// ToSet copies the elements of the current version of the set into a new Set
+func (s *COWSet) ToSet() *Set {
+	return s.Snapshot().ToSet()
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *COWSet) Union(t *COWSet) *COWSet {
+	return cowFrom(s.Snapshot().Union(t.Snapshot()))
+}
+
+// cowFrom creates a new COWSet whose first version is the specified persistent set
+func cowFrom(p *PersistentSet) *COWSet {
+	out := &COWSet{}
+	out.current.Store(p)
+
+	return out
+}
+
+// update applies a function to each value in turn, starting from the current version, and publishes
+// the final version.  For each value, it reports whether or not the function produced a new version.
+func (s *COWSet) update(values []interface{}, fn func(*PersistentSet, interface{}) *PersistentSet) []bool {
+	// Lock set for write, so that no other writer's version is lost
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Apply all changes, recording results
+	p := s.Snapshot()
+	results := make([]bool, len(values))
+	for i, v := range values {
+		next := fn(p, v)
+		results[i] = next != p
+		p = next
+	}
+
+	// Publish the new version
+	s.current.Store(p)
+	return results
+}
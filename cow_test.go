@@ -0,0 +1,116 @@
+package set
+
+import (
+	"log"
+	"sync"
+	"testing"
+)
+
+// TestCOWSet verifies that the basic methods of a COWSet are working properly
+func TestCOWSet(t *testing.T) {
+	log.Println("TestCOWSet()")
+
+	var zero COWSet
+	if zero.Size() != 0 || zero.Has(1) || !zero.Add(1) || !zero.Has(1) {
+		t.Fatalf("set.COWSet - zero value is not an empty set")
+	}
+
+	s := NewCOW(1, 2, 3)
+	if !s.Add(4) || s.Add(4) || !s.Remove(1) || s.Remove(1) {
+		t.Fatalf("set.COWSet - unexpected Add or Remove result")
+	}
+
+	results := s.AddAll(4, 5, 5)
+	if results[0] || !results[1] || results[2] {
+		t.Fatalf("set.COWSet.AddAll() - unexpected result: %v", results)
+	}
+	results = s.RemoveAll(5, 6)
+	if !results[0] || results[1] {
+		t.Fatalf("set.COWSet.RemoveAll() - unexpected result: %v", results)
+	}
+
+	if !s.ToSet().Equal(New(2, 3, 4)) || s.String() != "{ 2 3 4 }" || s.Size() != 3 {
+		t.Fatalf("set.COWSet - unexpected contents: %s", s)
+	}
+
+	// Create a table of algebra tests and expected results
+	u := NewCOW(3, 4, 5)
+	var tests = []struct {
+		name   string
+		result *COWSet
+		target *Set
+	}{
+		{"Union", s.Union(u), New(2, 3, 4, 5)},
+		{"Intersection", s.Intersection(u), New(3, 4)},
+		{"Difference", s.Difference(u), New(2)},
+		{"SymmetricDifference", s.SymmetricDifference(u), New(2, 5)},
+		{"Clone", s.Clone(), New(2, 3, 4)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.ToSet().Equal(test.target) {
+			t.Fatalf("set.COWSet.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+	}
+
+	// Derived sets are independent of their source
+	c := s.Clone()
+	c.Add(10)
+	if s.Has(10) || !s.Equal(NewCOW(2, 3, 4)) || !c.Subset(s) || s.Subset(c) {
+		t.Fatalf("set.COWSet.Clone() - clone is not independent")
+	}
+}
+
+// TestCOWSetSnapshot verifies that snapshots are unaffected by later writes
+func TestCOWSetSnapshot(t *testing.T) {
+	log.Println("TestCOWSetSnapshot()")
+
+	s := NewCOW(1, 2, 3)
+	snap := s.Snapshot()
+
+	// Writers may proceed while a snapshot is being iterated
+	n := 0
+	for v := range s.All() {
+		s.Remove(v)
+		s.Add(v.(int) + 100)
+		n++
+	}
+
+	if n != 3 || !snap.ToSet().Equal(New(1, 2, 3)) || !s.ToSet().Equal(New(101, 102, 103)) {
+		t.Fatalf("set.COWSet.Snapshot() - unexpected result: %s, %s", snap, s)
+	}
+}
+
+// TestCOWSetConcurrent verifies that concurrent writers never lose each other's changes, and readers
+// always see a consistent version
+func TestCOWSetConcurrent(t *testing.T) {
+	log.Println("TestCOWSetConcurrent()")
+
+	s := NewCOW()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				// Each pair of elements is always added together
+				s.AddAll(i*1000+j, -(i*1000 + j + 1))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if snap := s.Snapshot(); snap.Size()%2 != 0 {
+					t.Errorf("set.COWSet.Snapshot() - inconsistent version of size %d", snap.Size())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Size() != 1600 {
+		t.Fatalf("set.COWSet.Size() - unexpected result: %d", s.Size())
+	}
+}
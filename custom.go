@@ -0,0 +1,801 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// CustomSet represents an unordered collection of unique values, where uniqueness is determined by a
+// user-supplied hash and equality function, rather than by Go's == operator.  This allows values which
+// cannot be used as map keys, such as slices, maps, and structs containing them, to be stored in a set,
+// and allows values to be compared by a custom notion of equality.
+//
+// The hash function must return equal hashes for any two values which the equality function reports
+// as equal.  Elements are grouped into buckets by hash, so a good hash function keeps buckets small.
+// Set algebra between two CustomSets assumes that both sets use equivalent functions.  A CustomSet is
+// safe for concurrent use, and must be created using NewWith.
+//
+// A CustomSet supports the same operations as Set, except for FrozenPowerSet, which requires elements
+// that can be compared using ==.  Decoded elements are added using the set's own hash and equality functions, so they
+// must accept the types produced by decoding.
+type CustomSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Hash function used to bucket elements
+	hash func(interface{}) uint64
+	// Equality function used to compare elements within a bucket
+	eq func(interface{}, interface{}) bool
+	// Elements of the set, bucketed by hash
+	buckets map[uint64][]interface{}
+	// Number of elements in the set
+	size int
+	// Options used when encoding and decoding the set as JSON
+	jsonOpts JSONOptions
+	// Subscribers notified of every element added or removed
+	subs []*subscription
+}
+
+// NewWith creates a new CustomSet which uses the specified hash and equality functions, optionally
+// adding initial elements to the set
+func NewWith(hash func(interface{}) uint64, eq func(interface{}, interface{}) bool, values ...interface{}) *CustomSet {
+	// Initialize set
+	s := CustomSet{
+		hash:    hash,
+		eq:      eq,
+		buckets: make(map[uint64][]interface{}),
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.add(v)
+	}
+
+	return &s
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed
+func (s *CustomSet) Add(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.add(value)
+}
+
+// AddAll inserts all elements into the set within a single critical section, returning a slice which
+// reports, for each element, whether or not it was newly added
+func (s *CustomSet) AddAll(values ...interface{}) []bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Add all values to set, recording results
+	results := make([]bool, len(values))
+	for i, v := range values {
+		results[i] = s.add(v)
+	}
+
+	return results
+}
+
+// AddIfAbsent inserts a new element into the set only if it is not already present, returning true
+// if the element already existed, or false if it was newly added by this call.  Like TryAdd, if the
+// hash or equality function panics for the element, AddIfAbsent returns false with an error.
+func (s *CustomSet) AddIfAbsent(value interface{}) (bool, error) {
+	added, err := s.TryAdd(value)
+	if err != nil {
+		return false, err
+	}
+
+	return !added, nil
+}
+
+// All returns an iterator over all elements in the set, in no particular order, for use with a
+// range loop.  The set is locked for read while iteration is in progress, so the loop body must not
+// modify the set.
+func (s *CustomSet) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		s.Each(yield)
+	}
+}
+
+// CartesianPairs returns an iterator over ordered pairs of every permutation between two sets, for use
+// with a range loop.  It behaves like Set.CartesianPairs.
+func (s *CustomSet) CartesianPairs(t *CustomSet) iter.Seq[Pair] {
+	return cartesianPairsOf(s.Enumerate, t.Enumerate)
+}
+
+// CartesianProduct returns a set containing ordered pairs of every permutation between two sets.
+// Pairs in the new set are hashed and compared using the functions of both sets.
+func (s *CustomSet) CartesianProduct(t *CustomSet) *CustomSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Pairs combine the functions of both sets
+	sh, th, seq, teq := s.hash, t.hash, s.eq, t.eq
+	cpSet := NewWith(func(v interface{}) uint64 {
+		p := v.(Pair)
+		return combineHash(sh(p.X), th(p.Y))
+	}, func(a interface{}, b interface{}) bool {
+		pa, pb := a.(Pair), b.(Pair)
+		return seq(pa.X, pb.X) && teq(pa.Y, pb.Y)
+	})
+
+	// Enumerate the source set
+	s.each(func(x interface{}) bool {
+		// Enumerate the target set
+		t.each(func(y interface{}) bool {
+			// Create pair, insert elements, insert into set
+			cpSet.add(Pair{
+				X: x,
+				Y: y,
+			})
+			return true
+		})
+		return true
+	})
+
+	return cpSet
+}
+
+// Clone copies the current set into a new, identical set
+func (s *CustomSet) Clone() *CustomSet {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy set into a new set, copying each bucket
+	outSet := s.empty()
+	for h, b := range s.buckets {
+		outSet.buckets[h] = append([]interface{}(nil), b...)
+	}
+	outSet.size = s.size
+
+	return outSet
+}
+
+// Combinations returns an iterator over all subsets of the set which contain exactly k elements, for use
+// with a range loop.  It behaves like Set.Combinations.
+func (s *CustomSet) Combinations(k int) iter.Seq[[]interface{}] {
+	return combinationsOf(s.Enumerate, k)
+}
+
+// CombinationsSize returns the number of subsets of the set which contain exactly k elements, without
+// generating any subsets.  If the number does not fit in an int, CombinationsSize returns false.
+func (s *CustomSet) CombinationsSize(k int) (int, bool) {
+	return combinationsSize(s.Size(), k)
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *CustomSet) Difference(t *CustomSet) *CustomSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set of differences between the sets
+	diffSet := s.empty()
+	s.each(func(e interface{}) bool {
+		if !t.has(e) {
+			diffSet.add(e)
+		}
+		return true
+	})
+
+	return diffSet
+}
+
+// Each calls a function for every element in the set, in no particular order, stopping early if the
+// function returns false.  The set is locked for read while the function is applied, so the function
+// must not modify the set.
+func (s *CustomSet) Each(fn func(interface{}) bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	s.each(fn)
+}
+
+// Enumerate returns an unordered slice of all elements in the set
+func (s *CustomSet) Enumerate() []interface{} {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice
+	values := make([]interface{}, 0, s.size)
+	s.each(func(e interface{}) bool {
+		values = append(values, e)
+		return true
+	})
+
+	return values
+}
+
+// Equal returns whether or not two sets have the same length and no differences, meaning they are equal
+func (s *CustomSet) Equal(t *CustomSet) bool {
+	return s.Size() == t.Size() && t.Subset(s)
+}
+
+// Filter applies a function over all elements of the set, and returns all elements which return true
+// when the function is applied.  The function is applied to a snapshot of the set, without holding any
+// lock, so it may safely access or modify the set.
+func (s *CustomSet) Filter(fn func(interface{}) bool) *CustomSet {
+	// Create a set to return with elements which match filter function
+	filterSet := s.empty()
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, add elements which it matches
+		if fn(e) {
+			filterSet.add(e)
+		}
+	}
+
+	return filterSet
+}
+
+// Format implements fmt.Formatter, printing the set with its elements in a canonical sorted order, in
+// the same way as Set.Format
+func (s *CustomSet) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "*set.CustomSet", s.Enumerate())
+}
+
+// Freeze creates a new FrozenSet containing the elements of the set, returning an error if any element
+// cannot be frozen.  Elements which are distinct in this set, but have the same encoding, become a
+// single element of the FrozenSet.
+func (s *CustomSet) Freeze() (FrozenSet, error) {
+	return NewFrozen(s.Enumerate()...)
+}
+
+// GobEncode implements gob.GobEncoder, using the same format as MarshalBinary
+func (s *CustomSet) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, using the same format as UnmarshalBinary
+func (s *CustomSet) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+// Has checks for membership of an element in the set
+func (s *CustomSet) Has(value interface{}) bool {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.has(value)
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *CustomSet) Intersection(t *CustomSet) *CustomSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set of intersections between the sets
+	intSet := s.empty()
+	s.each(func(e interface{}) bool {
+		if t.has(e) {
+			intSet.add(e)
+		}
+		return true
+	})
+
+	return intSet
+}
+
+// Map applies a function over all elements of the set, and returns the resulting set, which uses the
+// specified hash and equality functions, as the function may produce values of a different type.  The
+// function is applied to a snapshot of the set, without holding any lock, so it may safely access or
+// modify the set.
+func (s *CustomSet) Map(fn func(interface{}) interface{}, hash func(interface{}) uint64, eq func(interface{}, interface{}) bool) *CustomSet {
+	// Create a set to return with function applied
+	mapSet := NewWith(hash, eq)
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, capture result
+		mapSet.add(fn(e))
+	}
+
+	return mapSet
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, using the same format as Set.MarshalBinary, so
+// that a CustomSet can be decoded into a Set.  Only elements of the types supported by Set.MarshalBinary
+// can be encoded, and any other type returns an error.
+func (s *CustomSet) MarshalBinary() ([]byte, error) {
+	return appendBinaryValues([]byte{binaryVersion}, s.Enumerate())
+}
+
+// MarshalJSON implements json.Marshaler, encoding the set as a JSON array of its elements, in the same
+// way as Set.MarshalJSON
+func (s *CustomSet) MarshalJSON() ([]byte, error) {
+	// Lock set for read
+	s.mutex.RLock()
+	opts := s.jsonOpts
+	s.mutex.RUnlock()
+
+	return marshalJSONValues(s.Enumerate(), opts)
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the set using the syntax read by Parse, in
+// the same way as Set.MarshalText
+func (s *CustomSet) MarshalText() ([]byte, error) {
+	return appendTextValues(nil, s.Enumerate())
+}
+
+// PowerSet generates a set of all possible subsets, given the current set.  Each subset is a
+// *CustomSet using the same functions as this set, and subsets are hashed and compared by their contents.
+func (s *CustomSet) PowerSet() *CustomSet {
+	// Subsets are hashed independently of the order of their elements, and compared by value
+	pSet := NewWith(func(v interface{}) uint64 {
+		var h uint64
+		v.(*CustomSet).Each(func(e interface{}) bool {
+			h += combineHash(s.hash(e), 0)
+			return true
+		})
+		return h
+	}, func(a interface{}, b interface{}) bool {
+		return a.(*CustomSet).Equal(b.(*CustomSet))
+	})
+
+	// Begin with the empty set, and extend every subset found so far with each element in turn
+	subsets := []*CustomSet{s.empty()}
+	for _, e := range s.Enumerate() {
+		for _, sub := range subsets {
+			next := sub.Clone()
+			next.add(e)
+			subsets = append(subsets, next)
+		}
+	}
+
+	for _, sub := range subsets {
+		pSet.add(sub)
+	}
+
+	return pSet
+}
+
+// PowerSetSize returns the number of subsets in the power set of the set, without generating any
+// subsets.  If the number does not fit in an int, PowerSetSize returns false.
+func (s *CustomSet) PowerSetSize() (int, bool) {
+	return powerSetSize(s.Size())
+}
+
+// Reduce applies a function over all elements of the set, accumulating the results into a final result value.
+// The function is applied to a snapshot of the set, without holding any lock, so it may safely access or
+// modify the set.
+func (s *CustomSet) Reduce(value interface{}, fn func(interface{}, interface{}) interface{}) interface{} {
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		value = fn(value, e)
+	}
+
+	return value
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *CustomSet) Remove(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.remove(value)
+}
+
+// RemoveAll destroys all elements in the set within a single critical section, returning a slice which
+// reports, for each element, whether or not it was destroyed
+func (s *CustomSet) RemoveAll(values ...interface{}) []bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Remove all values from set, recording results
+	results := make([]bool, len(values))
+	for i, v := range values {
+		results[i] = s.remove(v)
+	}
+
+	return results
+}
+
+// SetJSONOptions sets the options used when encoding and decoding the set as JSON
+func (s *CustomSet) SetJSONOptions(opts JSONOptions) {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.jsonOpts = opts
+}
+
+// Size returns the size or cardinality of this set
+func (s *CustomSet) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.size
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *CustomSet) String() string {
+	// Print identifier
+	str := "{ "
+
+	// Check for empty set, print symbol if empty
+	values := s.Enumerate()
+	if len(values) == 0 {
+		return str + "Ø }"
+	}
+
+	// Print all elements
+	SortElements(values)
+	for _, v := range values {
+		str = str + formatElement(v, false) + " "
+	}
+
+	return str + "}"
+}
+
+// Subscribe registers a callback which receives an Event for every element added to or removed from
+// the set.  It behaves like Set.Subscribe.
+func (s *CustomSet) Subscribe(fn func(Event)) (unsubscribe func()) {
+	return subscribe(&s.mutex, &s.subs, &subscription{
+		fn: fn,
+	})
+}
+
+// SubscribeChan returns a channel which receives an Event for every element added to or removed from
+// the set.  It behaves like Set.SubscribeChan.
+func (s *CustomSet) SubscribeChan(buffer int, policy BackPressure) (<-chan Event, func()) {
+	sub := newChanSubscription(buffer, policy)
+	return sub.ch, subscribe(&s.mutex, &s.subs, sub)
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *CustomSet) Subset(t *CustomSet) bool {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Check that every element of the parameter set is present
+	subset := true
+	t.each(func(e interface{}) bool {
+		subset = s.has(e)
+		return subset
+	})
+
+	return subset
+}
+
+// Subsets returns an iterator over all possible subsets of the set, for use with a range loop.  It
+// behaves like Set.Subsets.
+func (s *CustomSet) Subsets() iter.Seq[[]interface{}] {
+	return subsetsOf(s.Enumerate)
+}
+
+// Swap atomically replaces an element in the set with a new element, returning true if the old element
+// was present and has been replaced, or false if the old element did not exist, in which case the set
+// is left unchanged
+func (s *CustomSet) Swap(oldValue interface{}, newValue interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Only swap if the old value exists
+	if !s.remove(oldValue) {
+		return false
+	}
+
+	s.add(newValue)
+	return true
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *CustomSet) SymmetricDifference(t *CustomSet) *CustomSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set of elements present in only one of the sets
+	symSet := s.empty()
+	s.each(func(e interface{}) bool {
+		if !t.has(e) {
+			symSet.add(e)
+		}
+		return true
+	})
+	t.each(func(e interface{}) bool {
+		if !s.has(e) {
+			symSet.add(e)
+		}
+		return true
+	})
+
+	return symSet
+}
+
+// ToSet copies the elements of the current set into a new Set, returning an error if any element
+// cannot be used as an element of a Set.  Elements which are distinct in this set, but equal using
+// ==, become a single element of the new set.
+func (s *CustomSet) ToSet() (*Set, error) {
+	outSet := New()
+	for _, v := range s.Enumerate() {
+		if _, err := outSet.TryAdd(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return outSet, nil
+}
+
+// TryAdd inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  Unlike Add, if the hash or equality function panics for the element, such as
+// for a value of an unexpected type, TryAdd recovers and returns an error describing it.
+func (s *CustomSet) TryAdd(value interface{}) (bool, error) {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.tryAdd(value)
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *CustomSet) Union(t *CustomSet) *CustomSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set containing the elements of both sets
+	unionSet := s.empty()
+	for _, set := range []*CustomSet{s, t} {
+		set.each(func(e interface{}) bool {
+			unionSet.add(e)
+			return true
+		})
+	}
+
+	return unionSet
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding a set which was encoded using
+// MarshalBinary, and replacing the contents of the current set
+func (s *CustomSet) UnmarshalBinary(b []byte) error {
+	values, err := unmarshalBinaryValues(b)
+	if err != nil {
+		return err
+	}
+
+	// Lock set for write, and replace its contents
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replace(values)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a JSON array into the set in the same way as
+// Set.UnmarshalJSON, and replacing its contents
+func (s *CustomSet) UnmarshalJSON(b []byte) error {
+	// Lock set for read
+	s.mutex.RLock()
+	opts := s.jsonOpts
+	s.mutex.RUnlock()
+
+	values, err := unmarshalJSONValues(b, opts)
+	if err != nil {
+		return err
+	}
+
+	// Lock set for write, and replace its contents
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replace(values)
+	return nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding a set using Parse, and replacing the
+// contents of the current set
+func (s *CustomSet) UnmarshalText(b []byte) error {
+	t, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+
+	// Lock set for write, and replace its contents
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replace(t.Enumerate())
+	return nil
+}
+
+// Update runs a function within a read-write transaction, holding the set's write lock for the whole
+// of the function.  It behaves like Set.Update.
+func (s *CustomSet) Update(fn func(tx *Tx) error) error {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return updateTx(s, &s.subs, fn)
+}
+
+// View runs a function within a read-only transaction, holding the set's read lock for the whole of
+// the function.  It behaves like Set.View.
+func (s *CustomSet) View(fn func(tx *Tx)) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	viewTx(s, fn)
+}
+
+// add inserts a value into the set, returning true if it was newly added.  The caller must hold the
+// write lock.
+func (s *CustomSet) add(value interface{}) bool {
+	// Check existence within the bucket
+	h := s.hash(value)
+	for _, e := range s.buckets[h] {
+		if s.eq(e, value) {
+			return false
+		}
+	}
+
+	// Add value to bucket, and notify subscribers
+	s.buckets[h] = append(s.buckets[h], value)
+	s.size++
+	notifyAll(s.subs, EventAdd, value)
+	return true
+}
+
+// checkValue returns an error if the hash or equality function panics for a value, so that panics
+// raised by subscribers are never mistaken for it.  The caller must hold the lock.
+func (s *CustomSet) checkValue(value interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("set: cannot use element of type %T: %v", value, r)
+		}
+	}()
+
+	s.has(value)
+	return nil
+}
+
+// each calls a function for every element in the set, stopping early if it returns false.  The caller
+// must hold the lock.
+func (s *CustomSet) each(fn func(interface{}) bool) {
+	for _, b := range s.buckets {
+		for _, e := range b {
+			if !fn(e) {
+				return
+			}
+		}
+	}
+}
+
+// empty creates a new, empty set with the same functions as this set
+func (s *CustomSet) empty() *CustomSet {
+	return NewWith(s.hash, s.eq)
+}
+
+// has checks for membership of a value in the set.  The caller must hold the lock.
+func (s *CustomSet) has(value interface{}) bool {
+	for _, e := range s.buckets[s.hash(value)] {
+		if s.eq(e, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// remove destroys a value in the set, returning true if it existed.  The caller must hold the write
+// lock.
+func (s *CustomSet) remove(value interface{}) bool {
+	// Find the value within its bucket
+	h := s.hash(value)
+	b := s.buckets[h]
+	for i, e := range b {
+		if !s.eq(e, value) {
+			continue
+		}
+
+		// Remove value from bucket, deleting the bucket once empty
+		if len(b) == 1 {
+			delete(s.buckets, h)
+		} else {
+			b[i] = b[len(b)-1]
+			b[len(b)-1] = nil
+			s.buckets[h] = b[:len(b)-1]
+		}
+
+		s.size--
+		notifyAll(s.subs, EventRemove, value)
+		return true
+	}
+
+	return false
+}
+
+// replace replaces the contents of the set with a slice of values, notifying subscribers of each element
+// which was removed or added.  The caller must hold the write lock.
+func (s *CustomSet) replace(values []interface{}) {
+	// Build the new contents
+	next := s.empty()
+	for _, v := range values {
+		next.add(v)
+	}
+
+	old := &CustomSet{hash: s.hash, eq: s.eq, buckets: s.buckets}
+	s.buckets, s.size = next.buckets, next.size
+
+	// Avoid comparing the sets when nobody is listening
+	if len(s.subs) == 0 {
+		return
+	}
+
+	old.each(func(e interface{}) bool {
+		if !s.has(e) {
+			notifyAll(s.subs, EventRemove, e)
+		}
+		return true
+	})
+	s.each(func(e interface{}) bool {
+		if !old.has(e) {
+			notifyAll(s.subs, EventAdd, e)
+		}
+		return true
+	})
+}
+
+// tryAdd inserts a value into the set, returning true if it was newly added, or an error if the hash
+// or equality function panics for the value.  The caller must hold the write lock.
+func (s *CustomSet) tryAdd(value interface{}) (bool, error) {
+	if err := s.checkValue(value); err != nil {
+		return false, err
+	}
+
+	return s.add(value), nil
+}
+
+// txAdd implements txStore for CustomSet
+func (s *CustomSet) txAdd(value interface{}) (bool, error) {
+	return s.tryAdd(value)
+}
+
+// txHas implements txStore for CustomSet
+func (s *CustomSet) txHas(value interface{}) bool {
+	return s.has(value)
+}
+
+// txRemove implements txStore for CustomSet
+func (s *CustomSet) txRemove(value interface{}) bool {
+	return s.remove(value)
+}
+
+// txSize implements txStore for CustomSet
+func (s *CustomSet) txSize() int {
+	return s.size
+}
+
+// combineHash mixes two hashes into one, such that the result depends on the order of its arguments
+func combineHash(x uint64, y uint64) uint64 {
+	// Multiply by a large odd constant to spread the bits of x before mixing in y
+	x *= 0x9e3779b97f4a7c15
+	x ^= x >> 32
+	return x ^ (y + 0x632be59bd9b4e019 + (x << 6) + (x >> 2))
+}
+
+// errUnhashable returns an error describing a value which cannot be used as an element of a Set
+func errUnhashable(value interface{}) error {
+	return fmt.Errorf("set: element of type %T is not hashable, use NewWith to store it", value)
+}
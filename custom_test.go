@@ -0,0 +1,413 @@
+package set
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strings"
+	"testing"
+)
+
+// hashBytes hashes a []byte element, for use with NewWith
+func hashBytes(v interface{}) uint64 {
+	h := fnv.New64a()
+	h.Write(v.([]byte))
+	return h.Sum64()
+}
+
+// equalBytes compares two []byte elements, for use with NewWith
+func equalBytes(a interface{}, b interface{}) bool {
+	return bytes.Equal(a.([]byte), b.([]byte))
+}
+
+// newFold creates a CustomSet of case-insensitive strings
+func newFold(values ...interface{}) *CustomSet {
+	return NewWith(func(v interface{}) uint64 {
+		return hashBytes([]byte(strings.ToLower(v.(string))))
+	}, func(a interface{}, b interface{}) bool {
+		return strings.EqualFold(a.(string), b.(string))
+	}, values...)
+}
+
+// newBytes creates a CustomSet of []byte elements from strings
+func newBytes(values ...string) *CustomSet {
+	s := NewWith(hashBytes, equalBytes)
+	for _, v := range values {
+		s.Add([]byte(v))
+	}
+
+	return s
+}
+
+// TestCustomSet verifies that the basic methods of a CustomSet are working properly
+func TestCustomSet(t *testing.T) {
+	log.Println("TestCustomSet()")
+
+	s := newBytes("a", "b")
+	if !s.Add([]byte("c")) || s.Add([]byte("a")) || s.Size() != 3 {
+		t.Fatalf("set.CustomSet.Add() - unexpected result: %s", s)
+	}
+	if !s.Has([]byte("b")) || s.Has([]byte("d")) {
+		t.Fatalf("set.CustomSet.Has() - unexpected result")
+	}
+	if !s.Remove([]byte("b")) || s.Remove([]byte("b")) || s.Size() != 2 {
+		t.Fatalf("set.CustomSet.Remove() - unexpected result: %s", s)
+	}
+	if r := s.AddAll([]byte("a"), []byte("d")); r[0] || !r[1] {
+		t.Fatalf("set.CustomSet.AddAll() - unexpected result: %v", r)
+	}
+	if r := s.RemoveAll([]byte("d"), []byte("e")); !r[0] || r[1] {
+		t.Fatalf("set.CustomSet.RemoveAll() - unexpected result: %v", r)
+	}
+	if s.String() != "{ [97] [99] }" {
+		t.Fatalf("set.CustomSet.String() - unexpected result: %s", s)
+	}
+
+	// Elements which collide are kept apart by the equality function
+	c := NewWith(func(interface{}) uint64 { return 0 }, equalBytes, []byte("x"), []byte("y"), []byte("x"))
+	if c.Size() != 2 || !c.Remove([]byte("x")) || !c.Has([]byte("y")) || c.Has([]byte("x")) {
+		t.Fatalf("set.CustomSet - unexpected result with colliding hashes: %s", c)
+	}
+
+	// Custom equality may differ from ==
+	fold := newFold("Go", "GO", "go")
+	if fold.Size() != 1 || !fold.Has("gO") {
+		t.Fatalf("set.CustomSet - unexpected result with custom equality: %s", fold)
+	}
+}
+
+// TestCustomSetAlgebra verifies that set algebra on a CustomSet is working properly
+func TestCustomSetAlgebra(t *testing.T) {
+	log.Println("TestCustomSetAlgebra()")
+
+	x := newBytes("a", "b", "c")
+	y := newBytes("b", "c", "d")
+
+	// Create a table of tests and expected results
+	var tests = []struct {
+		name   string
+		result *CustomSet
+		target *CustomSet
+	}{
+		{"Union", x.Union(y), newBytes("a", "b", "c", "d")},
+		{"Intersection", x.Intersection(y), newBytes("b", "c")},
+		{"Difference", x.Difference(y), newBytes("a")},
+		{"SymmetricDifference", x.SymmetricDifference(y), newBytes("a", "d")},
+		{"Clone", x.Clone(), x},
+		{"Filter", x.Filter(func(v interface{}) bool { return v.([]byte)[0] != 'a' }), newBytes("b", "c")},
+		{"Map", x.Map(func(v interface{}) interface{} { return bytes.ToUpper(v.([]byte)) }, hashBytes, equalBytes), newBytes("A", "B", "C")},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.CustomSet.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+	}
+
+	if !x.Subset(newBytes("a", "c")) || x.Subset(y) || x.Equal(y) {
+		t.Fatalf("set.CustomSet.Subset() - unexpected result")
+	}
+
+	// Cloned sets are independent
+	c := x.Clone()
+	c.Remove([]byte("a"))
+	if !x.Has([]byte("a")) {
+		t.Fatalf("set.CustomSet.Clone() - clone is not independent")
+	}
+
+	n := x.Reduce(0, func(acc interface{}, v interface{}) interface{} {
+		return acc.(int) + len(v.([]byte))
+	})
+	if n != 3 || len(x.Enumerate()) != 3 {
+		t.Fatalf("set.CustomSet.Reduce() - unexpected result: %v", n)
+	}
+
+	count := 0
+	for range x.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatalf("set.CustomSet.All() - iteration did not stop")
+	}
+}
+
+// TestCustomSetOperations verifies that the remaining operations of Set are working properly on a CustomSet
+func TestCustomSetOperations(t *testing.T) {
+	log.Println("TestCustomSetOperations()")
+
+	s := newBytes("a", "b")
+	if existed, err := s.AddIfAbsent([]byte("c")); existed || err != nil {
+		t.Fatalf("set.CustomSet.AddIfAbsent() - unexpected result: %s, %v", s, err)
+	}
+	if existed, err := s.AddIfAbsent([]byte("a")); !existed || err != nil {
+		t.Fatalf("set.CustomSet.AddIfAbsent() - unexpected result: %s, %v", s, err)
+	}
+	if existed, err := newFold().AddIfAbsent(1); existed || err == nil {
+		t.Fatalf("set.CustomSet.AddIfAbsent() - expected error for invalid element")
+	}
+	if !s.Swap([]byte("c"), []byte("d")) || s.Swap([]byte("c"), []byte("e")) || !s.Equal(newBytes("a", "b", "d")) {
+		t.Fatalf("set.CustomSet.Swap() - unexpected result: %s", s)
+	}
+
+	// Subsets and combinations are produced without requiring hashable elements
+	subsets := 0
+	for range s.Subsets() {
+		subsets++
+	}
+	if n, ok := s.PowerSetSize(); subsets != 8 || n != 8 || !ok {
+		t.Fatalf("set.CustomSet.Subsets() - unexpected count: %d, %d", subsets, n)
+	}
+	pairs := 0
+	for c := range s.Combinations(2) {
+		if len(c) != 2 {
+			t.Fatalf("set.CustomSet.Combinations() - unexpected subset: %v", c)
+		}
+		pairs++
+	}
+	if n, ok := s.CombinationsSize(2); pairs != 3 || n != 3 || !ok {
+		t.Fatalf("set.CustomSet.Combinations() - unexpected count: %d, %d", pairs, n)
+	}
+
+	// Map may produce elements of another type
+	lengths := s.Map(func(v interface{}) interface{} {
+		return len(v.([]byte))
+	}, func(v interface{}) uint64 {
+		return uint64(v.(int))
+	}, func(a interface{}, b interface{}) bool {
+		return a == b
+	})
+	if lengths.Size() != 1 || !lengths.Has(1) {
+		t.Fatalf("set.CustomSet.Map() - unexpected result: %s", lengths)
+	}
+
+	// Subscribers see every change
+	var events []Event
+	unsubscribe := s.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+	s.Add([]byte("e"))
+	s.Remove([]byte("a"))
+	s.Add([]byte("b"))
+	unsubscribe()
+	s.Add([]byte("f"))
+	if len(events) != 2 || events[0].Kind != EventAdd || events[1].Kind != EventRemove || !bytes.Equal(events[1].Value.([]byte), []byte("a")) {
+		t.Fatalf("set.CustomSet.Subscribe() - unexpected events: %v", events)
+	}
+
+	// Elements which cannot be encoded return an error
+	if _, err := s.MarshalBinary(); err == nil {
+		t.Fatalf("set.CustomSet.MarshalBinary() - expected error for []byte elements")
+	}
+
+	// Elements are printed in canonical order
+	if str := fmt.Sprintf("%.2v|%d", newFold("c", "a", "b"), newFold()); str != "{ a b … (1 more) }|%!d(*set.CustomSet={ Ø })" {
+		t.Fatalf("set.CustomSet.Format() - unexpected result: %s", str)
+	}
+
+	pairs = 0
+	for p := range newBytes("a", "b").CartesianPairs(newBytes("c")) {
+		if !bytes.Equal(p.Y.([]byte), []byte("c")) {
+			t.Fatalf("set.CustomSet.CartesianPairs() - unexpected pair: %v", p)
+		}
+		pairs++
+	}
+	if pairs != 2 {
+		t.Fatalf("set.CustomSet.CartesianPairs() - unexpected count: %d", pairs)
+	}
+
+	// A panicking hash function is reported as an error
+	f := newFold("a")
+	if added, err := f.TryAdd(1); added || err == nil || f.Size() != 1 {
+		t.Fatalf("set.CustomSet.TryAdd() - unexpected result for invalid element: %t, %v", added, err)
+	}
+	if added, err := f.TryAdd("B"); !added || err != nil {
+		t.Fatalf("set.CustomSet.TryAdd() - unexpected result: %t, %v", added, err)
+	}
+}
+
+// TestCustomSetUpdate verifies that transactions against a CustomSet commit and roll back in the same
+// way as transactions against a Set
+func TestCustomSetUpdate(t *testing.T) {
+	log.Println("TestCustomSetUpdate()")
+
+	s := newBytes("a", "b")
+	events := 0
+	s.Subscribe(func(Event) {
+		events++
+	})
+
+	// Changes are rolled back on error, without notifying subscribers
+	errFail := errors.New("fail")
+	err := s.Update(func(tx *Tx) error {
+		tx.Remove([]byte("a"))
+		tx.Add([]byte("c"))
+		return errFail
+	})
+	if err != errFail || !s.Equal(newBytes("a", "b")) || events != 0 {
+		t.Fatalf("set.CustomSet.Update() - changes not rolled back: %s, %d events", s, events)
+	}
+
+	// Changes are committed on success, and subscribers notified
+	err = s.Update(func(tx *Tx) error {
+		if !tx.Has([]byte("a")) || tx.Add([]byte("a")) {
+			return errors.New("unexpected result within transaction")
+		}
+		tx.Remove([]byte("a"))
+		_, err := tx.TryAdd([]byte("c"))
+		return err
+	})
+	if err != nil || !s.Equal(newBytes("b", "c")) || events != 2 {
+		t.Fatalf("set.CustomSet.Update() - unexpected result: %s, %v, %d events", s, err, events)
+	}
+
+	// Invalid elements are reported as errors, rather than panicking
+	err = newFold().Update(func(tx *Tx) error {
+		_, err := tx.TryAdd(1)
+		return err
+	})
+	if err == nil {
+		t.Fatalf("set.CustomSet.Update() - expected error for invalid element")
+	}
+
+	s.View(func(tx *Tx) {
+		if tx.Size() != 2 || !tx.Has([]byte("c")) || tx.Has([]byte("a")) {
+			t.Errorf("set.CustomSet.View() - unexpected result within transaction")
+		}
+	})
+}
+
+// TestCustomSetEncoding verifies that a CustomSet can be encoded and decoded using every format supported
+// by Set, and that decoded elements are compared using the set's own functions
+func TestCustomSetEncoding(t *testing.T) {
+	log.Println("TestCustomSetEncoding()")
+
+	s := newFold("Go", "rust")
+	var tests = []struct {
+		name      string
+		marshal   func() ([]byte, error)
+		unmarshal func(*CustomSet, []byte) error
+		decode    func(*Set, []byte) error
+	}{
+		{"JSON", s.MarshalJSON, (*CustomSet).UnmarshalJSON, (*Set).UnmarshalJSON},
+		{"Binary", s.MarshalBinary, (*CustomSet).UnmarshalBinary, (*Set).UnmarshalBinary},
+		{"Text", s.MarshalText, (*CustomSet).UnmarshalText, (*Set).UnmarshalText},
+		{"Gob", s.GobEncode, (*CustomSet).GobDecode, (*Set).GobDecode},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		b, err := test.marshal()
+		if err != nil {
+			t.Fatalf("set.CustomSet.Marshal%s() - unexpected error: %v", test.name, err)
+		}
+
+		// Decoding replaces the contents of the set, notifying subscribers
+		d := newFold("RUST", "c")
+		events := 0
+		d.Subscribe(func(Event) {
+			events++
+		})
+		if err := test.unmarshal(d, b); err != nil {
+			t.Fatalf("set.CustomSet.Unmarshal%s() - unexpected error: %v", test.name, err)
+		}
+		if !d.Equal(s) || !d.Has("GO") || events != 2 {
+			t.Fatalf("set.CustomSet.Unmarshal%s() - unexpected result: %s, %d events", test.name, d, events)
+		}
+
+		// The encoding can also be read by a Set
+		u := New()
+		if err := test.decode(u, b); err != nil || !u.Equal(New("Go", "rust")) {
+			t.Fatalf("set.Unmarshal%s() - unexpected result: %s, %v", test.name, u, err)
+		}
+	}
+
+	if err := s.UnmarshalJSON([]byte("{")); err == nil || s.Size() != 2 {
+		t.Fatalf("set.CustomSet.UnmarshalJSON() - expected error for invalid input")
+	}
+}
+
+// TestCustomSetNested verifies that the CartesianProduct and PowerSet methods of a CustomSet produce
+// sets which compare their elements by value
+func TestCustomSetNested(t *testing.T) {
+	log.Println("TestCustomSetNested()")
+
+	x := newBytes("a", "b")
+	y := newBytes("c")
+
+	cp := x.CartesianProduct(y)
+	if cp.Size() != 2 || !cp.Has(Pair{[]byte("a"), []byte("c")}) || cp.Has(Pair{[]byte("c"), []byte("a")}) {
+		t.Fatalf("set.CustomSet.CartesianProduct() - unexpected result: %s", cp)
+	}
+
+	ps := x.PowerSet()
+	if ps.Size() != 4 || !ps.Has(newBytes()) || !ps.Has(newBytes("b", "a")) || ps.Has(newBytes("c")) {
+		t.Fatalf("set.CustomSet.PowerSet() - unexpected result: %s", ps)
+	}
+}
+
+// TestTryAdd verifies that the set.TryAdd() method returns an error for unhashable values
+func TestTryAdd(t *testing.T) {
+	log.Println("TestTryAdd()")
+
+	// Create a table of values, and whether or not they are hashable
+	var tests = []struct {
+		value    interface{}
+		hashable bool
+	}{
+		{1, true},
+		{"a", true},
+		{nil, true},
+		{Pair{1, 2}, true},
+		{[]byte("x"), false},
+		{map[int]int{}, false},
+		{struct{ s []int }{}, false},
+		{[1]interface{}{[]int{}}, false},
+		{Pair{1, []int{}}, false},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		s := New()
+		added, err := s.TryAdd(test.value)
+		if (err == nil) != test.hashable || added != test.hashable {
+			t.Fatalf("set.TryAdd(%v) - unexpected result: %t, %v", test.value, added, err)
+		}
+		if !test.hashable && s.Size() != 0 {
+			t.Fatalf("set.TryAdd(%v) - unhashable value added", test.value)
+		}
+
+		// The other entry points report unhashable values without panicking
+		if n := New(test.value).Size(); (n == 1) != test.hashable {
+			t.Fatalf("set.New(%v) - unexpected size: %d", test.value, n)
+		}
+		u := New(0)
+		if u.Add(test.value) != test.hashable || u.Has(test.value) != test.hashable || u.Remove(test.value) != test.hashable {
+			t.Fatalf("set.Add(%v) - unexpected result", test.value)
+		}
+		if r := u.AddAll(test.value); r[0] != test.hashable {
+			t.Fatalf("set.AddAll(%v) - unexpected result: %v", test.value, r)
+		}
+		u.Remove(test.value)
+		if u.Swap(0, test.value) != test.hashable || u.Has(0) == test.hashable {
+			t.Fatalf("set.Swap(%v) - unexpected result: %s", test.value, u)
+		}
+		if m := New(1).Map(func(interface{}) interface{} { return test.value }); (m.Size() == 1) != test.hashable {
+			t.Fatalf("set.Map() - unexpected result for %v: %s", test.value, m)
+		}
+		if n, err := TryNew(1, test.value); (err == nil) != test.hashable || (n != nil) != test.hashable {
+			t.Fatalf("set.TryNew(%v) - unexpected result: %v", test.value, err)
+		}
+	}
+
+	// Converting a CustomSet of unhashable values to a Set fails
+	if _, err := newBytes("a").ToSet(); err == nil {
+		t.Fatalf("set.CustomSet.ToSet() - expected error for unhashable values")
+	}
+	if s, err := NewWith(func(interface{}) uint64 { return 0 }, func(a, b interface{}) bool { return a == b }, 1, 2).ToSet(); err != nil || !s.Equal(New(1, 2)) {
+		t.Fatalf("set.CustomSet.ToSet() - unexpected result: %s, %v", s, err)
+	}
+}
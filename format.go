@@ -0,0 +1,193 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Format implements fmt.Formatter, printing the set with its elements in a canonical sorted order, so
+// that equal sets always print identically.  The following verbs and flags are supported:
+//
+//	%v, %s  elements of the set, for example { 1 2 (1, 3) }
+//	%+v     elements of the set along with their types, for example { int(1) string("a") }
+//
+// A precision, or if no precision is specified, a width, limits the number of elements printed.  For
+// example, %.2v prints the first two elements in sorted order, followed by a count of those remaining.
+func (s *Set) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "*set.Set", s.Enumerate())
+}
+
+// formatSet implements fmt.Formatter for a set of the named type, given a snapshot of its elements
+func formatSet(f fmt.State, verb rune, name string, values []interface{}) {
+	// Only value and string verbs are supported
+	if verb != 'v' && verb != 's' {
+		fmt.Fprintf(f, "%%!%c(%s=", verb, name)
+		formatSet(f, 'v', name, values)
+		fmt.Fprint(f, ")")
+		return
+	}
+
+	// Sort the elements into canonical order
+	SortElements(values)
+
+	// Print identifier
+	str := "{ "
+
+	// Check for empty set, print symbol if empty
+	if len(values) == 0 {
+		fmt.Fprint(f, str+"Ø }")
+		return
+	}
+
+	// Limit the number of elements printed, if requested
+	limit, ok := f.Precision()
+	if !ok {
+		limit, ok = f.Width()
+	}
+	if !ok || limit > len(values) {
+		limit = len(values)
+	}
+
+	// Print all elements, with the same flags as the set
+	typed := f.Flag('+')
+	for _, v := range values[:limit] {
+		str += formatElement(v, typed) + " "
+	}
+
+	// Summarize any elements which were not printed
+	if rest := len(values) - limit; rest > 0 {
+		str += fmt.Sprintf("… (%d more) ", rest)
+	}
+
+	fmt.Fprint(f, str+"}")
+}
+
+// formatElement returns a string representation of a single element of a set, optionally including
+// its type
+func formatElement(v interface{}, typed bool) string {
+	switch e := v.(type) {
+	case Pair:
+		// Print pairs separately
+		if typed {
+			return fmt.Sprintf("set.Pair(%s, %s)", formatElement(e.X, true), formatElement(e.Y, true))
+		}
+		return e.String()
+	case *Set:
+		if typed {
+			return fmt.Sprintf("*set.Set(%+v)", e)
+		}
+		return fmt.Sprintf("%v", e)
+	case string:
+		if typed {
+			return fmt.Sprintf("string(%q)", e)
+		}
+	}
+
+	if typed {
+		return fmt.Sprintf("%T(%v)", v, v)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// formatValues returns a string representation of a set containing the specified elements, which are
+// sorted into canonical order, as printed by Set.String
+func formatValues(values []interface{}) string {
+	// Check for empty set, print symbol if empty
+	if len(values) == 0 {
+		return "{ Ø }"
+	}
+
+	// Print all elements in canonical order
+	SortElements(values)
+
+	var b strings.Builder
+	b.WriteString("{ ")
+	for _, v := range values {
+		b.WriteString(formatElement(v, false))
+		b.WriteString(" ")
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+// SortElements sorts a slice of set elements into a canonical order, which is stable regardless of
+// the order in which the elements were added to a set.  Elements are grouped by type, and ordered
+// by value within each type: numerically for numbers, lexically for strings, false before true for
+// booleans, and by their printed form for all other types.
+func SortElements(values []interface{}) {
+	sort.SliceStable(values, func(i int, j int) bool {
+		return compareElements(values[i], values[j]) < 0
+	})
+}
+
+// compareElements compares two set elements using the canonical order used by SortElements
+func compareElements(a interface{}, b interface{}) int {
+	// Group elements by type, with nil first
+	if a == nil || b == nil {
+		return compareBool(a != nil, b != nil)
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return strings.Compare(ta.String(), tb.String())
+	}
+
+	// Order elements of the same type by value
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Bool:
+		return compareBool(va.Bool(), vb.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		// Order NaN before all other numbers
+		fa, fb := va.Float(), vb.Float()
+		if math.IsNaN(fa) || math.IsNaN(fb) {
+			return compareBool(!math.IsNaN(fa), !math.IsNaN(fb))
+		}
+		return compareOrdered(fa, fb)
+	case reflect.String:
+		return strings.Compare(va.String(), vb.String())
+	}
+
+	// Order pairs by their first, then second element
+	if pa, ok := a.(Pair); ok {
+		pb := b.(Pair)
+		if c := compareElements(pa.X, pb.X); c != 0 {
+			return c
+		}
+		return compareElements(pa.Y, pb.Y)
+	}
+
+	// Order everything else by its printed form
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+// compareBool compares two booleans, ordering false before true
+func compareBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareOrdered compares two ordered values
+func compareOrdered[T int64 | uint64 | float64](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
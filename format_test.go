@@ -0,0 +1,104 @@
+package set
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"testing"
+)
+
+// TestFormat verifies that the set.Format() method is working properly
+func TestFormat(t *testing.T) {
+	log.Println("TestFormat()")
+
+	// Create a table of tests and expected results of formatting
+	var tests = []struct {
+		format string
+		source *Set
+		result string
+	}{
+		// Empty set
+		{"%v", New(), "{ Ø }"},
+		// Numbers, in numeric order
+		{"%v", New(10, 2, 1, -3), "{ -3 1 2 10 }"},
+		// Mixed types, grouped by type
+		{"%v", New("b", 1, "a", 1.5, true, false), "{ false true 1.5 1 a b }"},
+		// NaN is ordered first
+		{"%v", New(1.0, math.NaN(), -1.0), "{ NaN -1 1 }"},
+		// Pairs, ordered by X then Y
+		{"%v", New(Pair{2, 1}, Pair{1, 2}, Pair{1, 1}), "{ (1, 1) (1, 2) (2, 1) }"},
+		// Nested sets
+		{"%v", New(New(2, 1), 0), "{ { 1 2 } 0 }"},
+		// String verb
+		{"%s", New(2, 1), "{ 1 2 }"},
+		// Element types
+		{"%+v", New(1, "a", Pair{int8(1), 2}), `{ int(1) set.Pair(int8(1), int(2)) string("a") }`},
+		// Element types in nested sets
+		{"%+v", New(New(1)), "{ *set.Set({ int(1) }) }"},
+		// Truncation by precision
+		{"%.2v", New(5, 4, 3, 2, 1), "{ 1 2 … (3 more) }"},
+		// Truncation by width
+		{"%3v", New(5, 4, 3, 2, 1), "{ 1 2 3 … (2 more) }"},
+		// Truncation larger than set
+		{"%.10v", New(2, 1), "{ 1 2 }"},
+		// Truncation to nothing
+		{"%.0v", New(2, 1), "{ … (2 more) }"},
+		// Unsupported verb
+		{"%d", New(1), "%!d(*set.Set={ 1 })"},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if str := fmt.Sprintf(test.format, test.source); str != test.result {
+			t.Fatalf("set.Format(%q) - unexpected result: %s != %s", test.format, str, test.result)
+		}
+	}
+}
+
+// TestStringDeterministic verifies that the set.String() method produces the same output for equal sets
+func TestStringDeterministic(t *testing.T) {
+	log.Println("TestStringDeterministic()")
+
+	// Build equal sets in different orders, checking output is identical
+	values := []interface{}{"x", 3, 2.5, Pair{1, 2}, "a", 1, false}
+	str := New(values...).String()
+	for i := 0; i < 20; i++ {
+		s := New()
+		for j := range values {
+			s.Add(values[(i+j)%len(values)])
+		}
+
+		if s.String() != str {
+			t.Fatalf("set.String() - unexpected result: %s != %s", s.String(), str)
+		}
+	}
+}
+
+// TestStringConcurrent verifies that the set.String() method is safe to use while the set is modified
+func TestStringConcurrent(t *testing.T) {
+	log.Println("TestStringConcurrent()")
+
+	s := New()
+
+	// Modify and print the set at the same time, relying on the race detector to catch errors
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			s.Add(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = s.String()
+		}
+	}()
+	wg.Wait()
+
+	if s.Size() != 1000 {
+		t.Fatalf("set.Size() - unexpected result: %d != %d", s.Size(), 1000)
+	}
+}
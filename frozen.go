@@ -0,0 +1,366 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// FrozenSet represents an immutable, unordered collection of unique values, which is itself a
+// comparable value.  Two FrozenSets are equal using == if and only if they contain equal elements, so
+// a FrozenSet can be used as a map key, or as an element of a Set, and membership checks compare
+// nested sets by their contents, rather than by pointer identity.
+//
+// A FrozenSet holds its elements in a canonical encoding, using the binary format of MarshalBinary, so
+// only elements of the types supported by MarshalBinary may be frozen.  Nested sets are frozen
+// recursively, so a *Set element becomes a FrozenSet element.  Elements are compared by their encoding,
+// after negative zero is replaced by zero, so that they are equal exactly when they are equal to a Set,
+// and every NaN is replaced by a single NaN, so that a NaN element is equal to itself.  The zero value
+// is an empty set.
+type FrozenSet struct {
+	// Canonical encoding of the elements: a uvarint count, followed by the sorted, encoded elements.
+	// The empty string represents the empty set.
+	key string
+	// Offset of each element within the key, as fixed width little endian integers, so that elements
+	// can be found without decoding the key.  It is derived from the key, so == still compares sets by
+	// their contents.
+	offsets string
+}
+
+// NewFrozen creates a new FrozenSet containing the specified elements, returning an error if any
+// element cannot be frozen
+func NewFrozen(values ...interface{}) (FrozenSet, error) {
+	// Freeze all elements, discarding duplicates
+	m := make(map[interface{}]struct{}, len(values))
+	for _, v := range values {
+		e, err := freezeElement(v)
+		if err != nil {
+			return FrozenSet{}, err
+		}
+
+		m[e] = struct{}{}
+	}
+
+	return frozenFromMap(m)
+}
+
+// Freeze creates a new FrozenSet containing the elements of the set, returning an error if any element
+// cannot be frozen
+func (s *Set) Freeze() (FrozenSet, error) {
+	return NewFrozen(s.Enumerate()...)
+}
+
+// FrozenPowerSet returns a set containing every subset of the set as a FrozenSet, so that subsets can
+// be found using Has, or compared using Equal.  It returns an error if any element cannot be frozen.
+func (s *Set) FrozenPowerSet() (*Set, error) {
+	// Freeze each subset as it is produced
+	pSet := New()
+	for subset := range s.Subsets() {
+		f, err := NewFrozen(subset...)
+		if err != nil {
+			return nil, err
+		}
+
+		pSet.m[f] = struct{}{}
+	}
+
+	return pSet, nil
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (f FrozenSet) Difference(g FrozenSet) FrozenSet {
+	return f.combine(g, func(inF bool, inG bool) bool {
+		return inF && !inG
+	})
+}
+
+// Enumerate returns a slice of all elements in the set, in the order of their encoding
+func (f FrozenSet) Enumerate() []interface{} {
+	values := make([]interface{}, 0, f.Size())
+	for _, e := range f.elements() {
+		v, err := readBinaryElement(bytes.NewReader([]byte(e)), math.MaxInt)
+		if err != nil {
+			// The key is always produced by this package, so it can never be corrupt, and its depth
+			// need not be limited
+			panic(err)
+		}
+
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets contain exactly the same elements, which is equivalent to ==
+func (f FrozenSet) Equal(g FrozenSet) bool {
+	return f == g
+}
+
+// Has checks for membership of an element in the set.  Elements which cannot be frozen are never members.
+func (f FrozenSet) Has(value interface{}) bool {
+	e, err := freezeElement(value)
+	if err != nil {
+		return false
+	}
+	b, err := appendBinaryElement(nil, e)
+	if err != nil {
+		return false
+	}
+
+	// Elements are sorted by their encoding, so search for the element
+	n := f.Size()
+	i := sort.Search(n, func(i int) bool {
+		return f.element(i) >= string(b)
+	})
+
+	return i < n && f.element(i) == string(b)
+}
+
+// Hash returns a hash of the contents of the set.  The hash is independent of the order in which
+// elements were added, and is stable across processes and releases, so equal sets always have
+// equal hashes.
+func (f FrozenSet) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(f.key))
+	return h.Sum64()
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (f FrozenSet) Intersection(g FrozenSet) FrozenSet {
+	return f.combine(g, func(inF bool, inG bool) bool {
+		return inF && inG
+	})
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, using the same format as Set.MarshalBinary, so
+// that a FrozenSet can be decoded into a Set
+func (f FrozenSet) MarshalBinary() ([]byte, error) {
+	return append([]byte{binaryVersion}, f.canonical()...), nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the set as a sorted JSON array of its elements
+func (f FrozenSet) MarshalJSON() ([]byte, error) {
+	return marshalJSONSet(f.Thaw(), JSONOptions{Sorted: true})
+}
+
+// Size returns the size or cardinality of this set
+func (f FrozenSet) Size() int {
+	return len(f.offsets) / frozenOffsetWidth(len(f.key))
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (f FrozenSet) String() string {
+	return f.Thaw().String()
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (f FrozenSet) Subset(g FrozenSet) bool {
+	return g.Difference(f).Size() == 0
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (f FrozenSet) SymmetricDifference(g FrozenSet) FrozenSet {
+	return f.combine(g, func(inF bool, inG bool) bool {
+		return inF != inG
+	})
+}
+
+// Thaw copies the elements of the set into a new, mutable Set.  Nested sets remain FrozenSets.
+func (f FrozenSet) Thaw() *Set {
+	outSet := New()
+	for _, v := range f.Enumerate() {
+		outSet.m[v] = struct{}{}
+	}
+
+	return outSet
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (f FrozenSet) Union(g FrozenSet) FrozenSet {
+	return f.combine(g, func(inF bool, inG bool) bool {
+		return inF || inG
+	})
+}
+
+// canonical returns the encoding of the set, including the count of an empty set
+func (f FrozenSet) canonical() string {
+	if f.key == "" {
+		return "\x00"
+	}
+
+	return f.key
+}
+
+// combine creates a new set by merging the sorted elements of two sets, keeping each element for which
+// the keep function returns true, given whether or not it is present in each set
+func (f FrozenSet) combine(g FrozenSet, keep func(bool, bool) bool) FrozenSet {
+	fe, ge := f.elements(), g.elements()
+
+	// Merge the sorted elements of both sets
+	var out []string
+	for len(fe) > 0 || len(ge) > 0 {
+		var c int
+		switch {
+		case len(fe) == 0:
+			c = 1
+		case len(ge) == 0:
+			c = -1
+		default:
+			c = bytes.Compare([]byte(fe[0]), []byte(ge[0]))
+		}
+
+		switch {
+		case c < 0:
+			if keep(true, false) {
+				out = append(out, fe[0])
+			}
+			fe = fe[1:]
+		case c > 0:
+			if keep(false, true) {
+				out = append(out, ge[0])
+			}
+			ge = ge[1:]
+		default:
+			if keep(true, true) {
+				out = append(out, fe[0])
+			}
+			fe, ge = fe[1:], ge[1:]
+		}
+	}
+
+	return frozenFromElements(out)
+}
+
+// element returns the encoding of the element at position i in sorted order
+func (f FrozenSet) element(i int) string {
+	end := len(f.key)
+	if i+1 < f.Size() {
+		end = f.offset(i + 1)
+	}
+
+	return f.key[f.offset(i):end]
+}
+
+// elements returns the encodings of each element, in sorted order
+func (f FrozenSet) elements() []string {
+	elements := make([]string, f.Size())
+	for i := range elements {
+		elements[i] = f.element(i)
+	}
+
+	return elements
+}
+
+// offset returns the offset within the key of the element at position i in sorted order
+func (f FrozenSet) offset(i int) int {
+	w := frozenOffsetWidth(len(f.key))
+	if w == 4 {
+		return int(binary.LittleEndian.Uint32([]byte(f.offsets[i*w:])))
+	}
+
+	return int(binary.LittleEndian.Uint64([]byte(f.offsets[i*w:])))
+}
+
+// freezeElement converts an element into a form which can be held by a FrozenSet, freezing nested
+// sets, and returns an error if the element cannot be encoded
+func freezeElement(v interface{}) (interface{}, error) {
+	switch e := v.(type) {
+	case *Set:
+		return e.Freeze()
+	case Pair:
+		x, err := freezeElement(e.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := freezeElement(e.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return Pair{X: x, Y: y}, nil
+	case float64:
+		// Canonicalize zeros and NaNs, which have more than one encoding
+		if e == 0 {
+			return float64(0), nil
+		} else if math.IsNaN(e) {
+			return math.NaN(), nil
+		}
+	case float32:
+		if e == 0 {
+			return float32(0), nil
+		} else if e != e {
+			return float32(math.NaN()), nil
+		}
+	}
+
+	// Check that the element can be encoded
+	if _, err := appendBinaryElement(nil, v); err != nil {
+		return nil, fmt.Errorf("set: cannot freeze element %v of type %T", v, v)
+	}
+
+	return v, nil
+}
+
+// frozenFromMap creates a FrozenSet from a map of frozen elements
+func frozenFromMap(m map[interface{}]struct{}) (FrozenSet, error) {
+	if len(m) == 0 {
+		return FrozenSet{}, nil
+	}
+
+	// Encode all elements, and sort them by their encoding
+	elements := make([]string, 0, len(m))
+	for k := range m {
+		e, err := appendBinaryElement(nil, k)
+		if err != nil {
+			return FrozenSet{}, err
+		}
+
+		elements = append(elements, string(e))
+	}
+	sort.Strings(elements)
+
+	return frozenFromElements(elements), nil
+}
+
+// frozenFromElements creates a FrozenSet from the sorted encodings of its elements
+func frozenFromElements(elements []string) FrozenSet {
+	if len(elements) == 0 {
+		return FrozenSet{}
+	}
+
+	// Encode the count and elements, recording the offset of each element
+	key := binary.AppendUvarint(nil, uint64(len(elements)))
+	starts := make([]int, len(elements))
+	for i, e := range elements {
+		starts[i] = len(key)
+		key = append(key, e...)
+	}
+
+	w := frozenOffsetWidth(len(key))
+	offsets := make([]byte, 0, w*len(elements))
+	for _, start := range starts {
+		if w == 4 {
+			offsets = binary.LittleEndian.AppendUint32(offsets, uint32(start))
+		} else {
+			offsets = binary.LittleEndian.AppendUint64(offsets, uint64(start))
+		}
+	}
+
+	return FrozenSet{key: string(key), offsets: string(offsets)}
+}
+
+// frozenOffsetWidth returns the width in bytes of each offset into a key of the specified length
+func frozenOffsetWidth(n int) int {
+	if uint64(n) <= math.MaxUint32 {
+		return 4
+	}
+
+	return 8
+}
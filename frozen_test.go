@@ -0,0 +1,193 @@
+package set
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// mustFreeze creates a FrozenSet for tests, failing the test on error
+func mustFreeze(t *testing.T, values ...interface{}) FrozenSet {
+	f, err := NewFrozen(values...)
+	if err != nil {
+		t.Fatalf("set.NewFrozen() - unexpected error: %v", err)
+	}
+
+	return f
+}
+
+// TestFrozenEquality verifies that FrozenSets are compared by value
+func TestFrozenEquality(t *testing.T) {
+	log.Println("TestFrozenEquality()")
+
+	// Create a table of pairs of sets, and whether or not they are equal
+	var tests = []struct {
+		x     []interface{}
+		y     []interface{}
+		equal bool
+	}{
+		{nil, nil, true},
+		{[]interface{}{1, 2, 3}, []interface{}{3, 2, 1, 1}, true},
+		{[]interface{}{1, 2}, []interface{}{1, 2, 3}, false},
+		// Types are significant
+		{[]interface{}{1}, []interface{}{int64(1)}, false},
+		{[]interface{}{1.0}, []interface{}{1}, false},
+		// Nested sets are compared by value
+		{[]interface{}{New(1, 2), Pair{New(3), "a"}}, []interface{}{New(2, 1), Pair{New(3), "a"}}, true},
+		{[]interface{}{New(1, 2)}, []interface{}{New(1)}, false},
+		// NaN is equal to itself, regardless of its payload
+		{[]interface{}{math.NaN()}, []interface{}{math.NaN()}, true},
+		{[]interface{}{math.NaN()}, []interface{}{math.Float64frombits(0x7ff8000000000001)}, true},
+		{[]interface{}{float32(math.NaN())}, []interface{}{math.Float32frombits(0xffc00001)}, true},
+		// Negative zero is equal to zero, as it is in a Set
+		{[]interface{}{0.0, math.Copysign(0, -1)}, []interface{}{0.0}, true},
+		{[]interface{}{Pair{math.Copysign(0, -1), float32(math.Copysign(0, -1))}}, []interface{}{Pair{0.0, float32(0)}}, true},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		x, y := mustFreeze(t, test.x...), mustFreeze(t, test.y...)
+		if (x == y) != test.equal || x.Equal(y) != test.equal || (x.Hash() == y.Hash()) != test.equal {
+			t.Fatalf("set.FrozenSet - unexpected equality of %s and %s", x, y)
+		}
+	}
+
+	// Membership of zeros matches a Set
+	if z := mustFreeze(t, 0.0, math.Copysign(0, -1)); z.Size() != New(0.0, math.Copysign(0, -1)).Size() || !z.Has(math.Copysign(0, -1)) {
+		t.Fatalf("set.NewFrozen() - unexpected result for zeros: %s", z)
+	}
+
+	// Frozen sets can be map keys
+	m := map[FrozenSet]int{mustFreeze(t, 1, 2): 1}
+	if m[mustFreeze(t, 2, 1)] != 1 {
+		t.Fatalf("set.FrozenSet - map lookup failed")
+	}
+
+	// Unsupported elements cannot be frozen
+	if _, err := NewFrozen(struct{}{}); err == nil {
+		t.Fatalf("set.NewFrozen() - expected error for unsupported type")
+	}
+}
+
+// TestFrozenMethods verifies that the methods of a FrozenSet are working properly
+func TestFrozenMethods(t *testing.T) {
+	log.Println("TestFrozenMethods()")
+
+	var empty FrozenSet
+	f := mustFreeze(t, 3, 1, "a", New(2))
+	g := mustFreeze(t, 1, "b")
+
+	if f.Size() != 4 || empty.Size() != 0 || !f.Has(1) || !f.Has(New(2)) || f.Has(2) || f.Has(struct{}{}) {
+		t.Fatalf("set.FrozenSet - unexpected membership: %s", f)
+	}
+	if f.String() != "{ 1 3 { 2 } a }" || empty.String() != "{ Ø }" {
+		t.Fatalf("set.FrozenSet.String() - unexpected result: %s", f)
+	}
+
+	// Thawed sets hold nested sets as FrozenSets
+	thawed := f.Thaw()
+	if !thawed.Has(mustFreeze(t, 2)) || thawed.Size() != 4 {
+		t.Fatalf("set.FrozenSet.Thaw() - unexpected result: %s", thawed)
+	}
+	if refrozen, _ := thawed.Freeze(); refrozen != f {
+		t.Fatalf("set.Set.Freeze() - unexpected result: %s != %s", refrozen, f)
+	}
+
+	// Create a table of algebra tests and expected results
+	var tests = []struct {
+		name   string
+		result FrozenSet
+		target FrozenSet
+	}{
+		{"Union", f.Union(g), mustFreeze(t, 1, 3, "a", "b", New(2))},
+		{"Intersection", f.Intersection(g), mustFreeze(t, 1)},
+		{"Difference", f.Difference(g), mustFreeze(t, 3, "a", New(2))},
+		{"SymmetricDifference", f.SymmetricDifference(g), mustFreeze(t, 3, "a", "b", New(2))},
+		{"Intersection", f.Intersection(empty), empty},
+		{"Difference", f.Difference(f), empty},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if test.result != test.target {
+			t.Fatalf("set.FrozenSet.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+	}
+
+	if !f.Subset(mustFreeze(t, 1, New(2))) || f.Subset(g) || !f.Subset(empty) || !empty.Subset(empty) {
+		t.Fatalf("set.FrozenSet.Subset() - unexpected result")
+	}
+	// Every element of a larger set is found, and no others
+	values := make([]interface{}, 0, 1000)
+	for i := 0; i < 1000; i += 2 {
+		values = append(values, i, strconv.Itoa(i))
+	}
+	large := mustFreeze(t, values...)
+	for i := -1; i < 1000; i++ {
+		if large.Has(i) != (i >= 0 && i%2 == 0) || large.Has(strconv.Itoa(i)) != (i >= 0 && i%2 == 0) {
+			t.Fatalf("set.FrozenSet.Has(%d) - unexpected result", i)
+		}
+	}
+}
+
+// TestFrozenPowerSet verifies that the set.FrozenPowerSet() method supports structural membership checks
+func TestFrozenPowerSet(t *testing.T) {
+	log.Println("TestFrozenPowerSet()")
+
+	ps, err := New(1, 2, 3).FrozenPowerSet()
+	if err != nil {
+		t.Fatalf("set.FrozenPowerSet() - unexpected error: %v", err)
+	}
+
+	if ps.Size() != 8 || !ps.Has(mustFreeze(t, 2, 1)) || !ps.Has(mustFreeze(t)) || ps.Has(mustFreeze(t, 4)) {
+		t.Fatalf("set.FrozenPowerSet() - unexpected result: %s", ps)
+	}
+
+	// Equal power sets are equal, as their elements are compared by value
+	other, _ := New(3, 2, 1).FrozenPowerSet()
+	if !ps.Equal(other) || ps.Difference(other).Size() != 0 {
+		t.Fatalf("set.FrozenPowerSet() - power sets not equal: %s != %s", ps, other)
+	}
+
+	if _, err := New(struct{}{}).FrozenPowerSet(); err == nil {
+		t.Fatalf("set.FrozenPowerSet() - expected error for unsupported type")
+	}
+}
+
+// TestFrozenEncoding verifies that FrozenSets can be encoded as elements of a Set
+func TestFrozenEncoding(t *testing.T) {
+	log.Println("TestFrozenEncoding()")
+
+	s := New(mustFreeze(t, 1, 2), FrozenSet{}, 3)
+
+	// Binary encoding round trips exactly
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("set.MarshalBinary() - unexpected error: %v", err)
+	}
+	out := New()
+	if err := out.UnmarshalBinary(b); err != nil {
+		t.Fatalf("set.UnmarshalBinary() - unexpected error: %v", err)
+	}
+	if !out.Equal(s) {
+		t.Fatalf("set.UnmarshalBinary() - sets not equal: %s != %s", out, s)
+	}
+
+	// A FrozenSet can be decoded into a Set
+	fb, _ := mustFreeze(t, "x", 1).MarshalBinary()
+	if err := out.UnmarshalBinary(fb); err != nil || !out.Equal(New("x", 1)) {
+		t.Fatalf("set.FrozenSet.MarshalBinary() - unexpected result: %s, %v", out, err)
+	}
+
+	// JSON and text encode FrozenSets as nested sets
+	j, err := json.Marshal(mustFreeze(t, 2, 1))
+	if err != nil || string(j) != "[1,2]" {
+		t.Fatalf("set.FrozenSet.MarshalJSON() - unexpected result: %s, %v", j, err)
+	}
+	txt, err := s.MarshalText()
+	if err != nil || string(txt) != "{ 3 { 1 2 } { Ø } }" {
+		t.Fatalf("set.MarshalText() - unexpected result: %s, %v", txt, err)
+	}
+}
@@ -0,0 +1,314 @@
+package set
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+)
+
+// IntSet represents an unordered collection of unique, small non-negative integers, backed by a
+// bitset.  Each possible element occupies a single bit, so an IntSet uses far less memory than a
+// Set for dense ranges of integers, and set algebra is performed a machine word at a time.
+//
+// Memory use is proportional to the largest element in the set, so IntSet is not suited to sparse
+// sets of very large integers, for which RoaringSet is better suited.  Negative integers, and integers
+// greater than MaxIntSetValue, can never be members of an IntSet.
+type IntSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Bits representing membership of each integer, 64 integers to a word
+	words []uint64
+}
+
+// MaxIntSetValue is the largest integer which can be a member of an IntSet.  A set holding it uses 256MiB.
+const MaxIntSetValue = 1<<31 - 1
+
+// NewInt creates a new IntSet, optionally adding initial elements to the set
+func NewInt(values ...int) *IntSet {
+	// Initialize set
+	s := IntSet{}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// IntSetFrom converts a Set of int elements into an IntSet, returning an error if any element of the
+// set is not an int, is negative, or is greater than MaxIntSetValue
+func IntSetFrom(t *Set) (*IntSet, error) {
+	// Create the output set
+	s := NewInt()
+
+	// Enumerate the source set, checking each element
+	for _, e := range t.Enumerate() {
+		v, ok := e.(int)
+		if !ok {
+			return nil, fmt.Errorf("set: element %v of type %T is not an int", e, e)
+		}
+		if v < 0 {
+			return nil, fmt.Errorf("set: element %d is negative", v)
+		}
+		if v > MaxIntSetValue {
+			return nil, fmt.Errorf("set: element %d is greater than %d", v, MaxIntSetValue)
+		}
+
+		s.Add(v)
+	}
+
+	return s, nil
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  Negative integers, and integers greater than MaxIntSetValue, cannot be added,
+// and always return false.
+func (s *IntSet) Add(value int) bool {
+	// Negative and overly large integers are never members
+	if value < 0 || value > MaxIntSetValue {
+		return false
+	}
+
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Grow the set to hold the value
+	w, b := value/64, uint64(1)<<uint(value%64)
+	if w >= len(s.words) {
+		s.words = append(s.words, make([]uint64, w-len(s.words)+1)...)
+	}
+
+	// Check existence, add value to set
+	if s.words[w]&b != 0 {
+		return false
+	}
+
+	s.words[w] |= b
+	return true
+}
+
+// Clone copies the current set into a new, identical set
+func (s *IntSet) Clone() *IntSet {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy words into a new set
+	words := make([]uint64, len(s.words))
+	copy(words, s.words)
+
+	return &IntSet{
+		words: words,
+	}
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *IntSet) Difference(t *IntSet) *IntSet {
+	return s.combine(t, firstLen, func(x uint64, y uint64) uint64 {
+		return x &^ y
+	})
+}
+
+// Enumerate returns a slice of all elements in the set, in ascending order
+func (s *IntSet) Enumerate() []int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice, visiting only the set bits of each word
+	values := make([]int, 0, s.size())
+	for i, w := range s.words {
+		for w != 0 {
+			values = append(values, i*64+bits.TrailingZeros64(w))
+			w &= w - 1
+		}
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets contain exactly the same elements
+func (s *IntSet) Equal(t *IntSet) bool {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Compare words, treating missing words as empty
+	for i := 0; i < max(len(s.words), len(t.words)); i++ {
+		if word(s.words, i) != word(t.words, i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Has checks for membership of an element in the set
+func (s *IntSet) Has(value int) bool {
+	// Negative integers are never members
+	if value < 0 {
+		return false
+	}
+
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return word(s.words, value/64)&(uint64(1)<<uint(value%64)) != 0
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *IntSet) Intersection(t *IntSet) *IntSet {
+	return s.combine(t, minLen, func(x uint64, y uint64) uint64 {
+		return x & y
+	})
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *IntSet) Remove(value int) bool {
+	// Negative integers are never members
+	if value < 0 {
+		return false
+	}
+
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence, remove value from set
+	w, b := value/64, uint64(1)<<uint(value%64)
+	if word(s.words, w)&b == 0 {
+		return false
+	}
+
+	s.words[w] &^= b
+	return true
+}
+
+// Size returns the size or cardinality of this set
+func (s *IntSet) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.size()
+}
+
+// String returns a string representation of this set, with elements in ascending order
+func (s *IntSet) String() string {
+	// Print identifier
+	str := "{ "
+
+	// Check for empty set, print symbol if empty
+	values := s.Enumerate()
+	if len(values) == 0 {
+		return str + "Ø }"
+	}
+
+	// Print all elements
+	for _, v := range values {
+		str = str + fmt.Sprintf("%d ", v)
+	}
+
+	return str + "}"
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *IntSet) Subset(t *IntSet) bool {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Check that no word of the parameter set has bits missing from the set
+	for i, w := range t.words {
+		if w&^word(s.words, i) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *IntSet) SymmetricDifference(t *IntSet) *IntSet {
+	return s.combine(t, maxLen, func(x uint64, y uint64) uint64 {
+		return x ^ y
+	})
+}
+
+// ToSet copies the elements of the current set into a new Set of int elements
+func (s *IntSet) ToSet() *Set {
+	// Copy set into a new set
+	outSet := New()
+	for _, v := range s.Enumerate() {
+		outSet.Add(v)
+	}
+
+	return outSet
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *IntSet) Union(t *IntSet) *IntSet {
+	return s.combine(t, maxLen, func(x uint64, y uint64) uint64 {
+		return x | y
+	})
+}
+
+// combine creates a new set by applying a function to each pair of words from the current set and
+// the parameter set.  The length function determines the number of words in the new set, given the
+// number of words in each set.
+func (s *IntSet) combine(t *IntSet, length func(int, int) int, fn func(uint64, uint64) uint64) *IntSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Combine words, treating missing words as empty
+	words := make([]uint64, length(len(s.words), len(t.words)))
+	for i := range words {
+		words[i] = fn(word(s.words, i), word(t.words, i))
+	}
+
+	return &IntSet{
+		words: words,
+	}
+}
+
+// size returns the number of set bits in the set.  The caller must hold the lock.
+func (s *IntSet) size() int {
+	size := 0
+	for _, w := range s.words {
+		size += bits.OnesCount64(w)
+	}
+
+	return size
+}
+
+// firstLen returns the number of words in the first set, for use with combine
+func firstLen(x int, _ int) int {
+	return x
+}
+
+// maxLen returns the number of words in the longer of two sets, for use with combine
+func maxLen(x int, y int) int {
+	return max(x, y)
+}
+
+// minLen returns the number of words in the shorter of two sets, for use with combine
+func minLen(x int, y int) int {
+	return min(x, y)
+}
+
+// word returns the word at index i, or zero if the index is beyond the end of the words
+func word(words []uint64, i int) uint64 {
+	if i < len(words) {
+		return words[i]
+	}
+
+	return 0
+}
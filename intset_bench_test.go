@@ -0,0 +1,97 @@
+package set
+
+import (
+	"testing"
+)
+
+// BenchmarkIntSetAdd checks the performance of the IntSet.Add() method
+func BenchmarkIntSetAdd(b *testing.B) {
+	// Create a new set
+	set := NewInt()
+
+	// Run set.Add() b.N times
+	for i := 0; i < b.N; i++ {
+		set.Add(i % 65536)
+	}
+}
+
+// BenchmarkIntSetHas checks the performance of the IntSet.Has() method
+func BenchmarkIntSetHas(b *testing.B) {
+	// Create a new set
+	set := NewInt()
+
+	// Run set.Has() b.N times
+	for i := 0; i < b.N; i++ {
+		set.Has(i % 65536)
+	}
+}
+
+// intSetRange creates an IntSet containing every n-th integer from zero up to max
+func intSetRange(n int, max int) *IntSet {
+	set := NewInt()
+	for i := 0; i < max; i += n {
+		set.Add(i)
+	}
+
+	return set
+}
+
+// benchmarkIntSetUnion checks the performance of the IntSet.Union() method
+func benchmarkIntSetUnion(n int, s *IntSet, t *IntSet) {
+	// Run set.Union() n times
+	for i := 0; i < n; i++ {
+		s.Union(t)
+	}
+}
+
+// BenchmarkIntSetUnionSmall checks the performance of the IntSet.Union() method
+// over a small data set
+func BenchmarkIntSetUnionSmall(b *testing.B) {
+	benchmarkIntSetUnion(b.N, NewInt(1, 2), NewInt(2, 1))
+}
+
+// BenchmarkIntSetUnionLarge checks the performance of the IntSet.Union() method
+// over a large data set
+func BenchmarkIntSetUnionLarge(b *testing.B) {
+	benchmarkIntSetUnion(b.N, intSetRange(2, 65536), intSetRange(3, 65536))
+}
+
+// benchmarkIntSetIntersection checks the performance of the IntSet.Intersection() method
+func benchmarkIntSetIntersection(n int, s *IntSet, t *IntSet) {
+	// Run set.Intersection() n times
+	for i := 0; i < n; i++ {
+		s.Intersection(t)
+	}
+}
+
+// BenchmarkIntSetIntersectionSmall checks the performance of the IntSet.Intersection() method
+// over a small data set
+func BenchmarkIntSetIntersectionSmall(b *testing.B) {
+	benchmarkIntSetIntersection(b.N, NewInt(1, 2), NewInt(2, 1))
+}
+
+// BenchmarkIntSetIntersectionLarge checks the performance of the IntSet.Intersection() method
+// over a large data set
+func BenchmarkIntSetIntersectionLarge(b *testing.B) {
+	benchmarkIntSetIntersection(b.N, intSetRange(2, 65536), intSetRange(3, 65536))
+}
+
+// benchmarkIntSetSize checks the performance of the IntSet.Size() method
+func benchmarkIntSetSize(n int, s *IntSet) {
+	// Run set.Size() n times
+	for i := 0; i < n; i++ {
+		s.Size()
+	}
+}
+
+// BenchmarkIntSetSizeSmall checks the performance of the IntSet.Size() method
+// over a small data set
+func BenchmarkIntSetSizeSmall(b *testing.B) {
+	benchmarkIntSetSize(b.N, NewInt(1, 2))
+}
+
+// BenchmarkIntSetSizeLarge checks the performance of the IntSet.Size() method
+// over a large data set
+func BenchmarkIntSetSizeLarge(b *testing.B) {
+	benchmarkIntSetSize(b.N, intSetRange(2, 65536))
+}
@@ -0,0 +1,132 @@
+package set
+
+import (
+	"log"
+	"math"
+	"testing"
+)
+
+// TestIntSetAddRemove verifies that the IntSet.Add(), IntSet.Has() and IntSet.Remove() methods are
+// working properly
+func TestIntSetAddRemove(t *testing.T) {
+	log.Println("TestIntSetAddRemove()")
+
+	// Create a set, add some initial values
+	set := NewInt(1, 3, 5)
+
+	// Create a table of tests and expected results for adding and removing elements
+	var tests = []struct {
+		element int
+		add     bool
+		remove  bool
+	}{
+		// New items, including items in later words
+		{2, true, true},
+		{64, true, true},
+		{1000, true, true},
+		// Existing items
+		{1, false, true},
+		{3, false, true},
+		// Negative items
+		{-1, false, false},
+		// Items beyond the largest allowed value
+		{math.MaxInt, false, false},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if ok := set.Add(test.element); ok != test.add {
+			t.Fatalf("set.Add(%d) - unexpected result: %t", test.element, ok)
+		}
+		if ok := set.Has(test.element); ok != test.remove {
+			t.Fatalf("set.Has(%d) - unexpected result: %t", test.element, ok)
+		}
+		if ok := set.Remove(test.element); ok != test.remove {
+			t.Fatalf("set.Remove(%d) - unexpected result: %t", test.element, ok)
+		}
+
+		log.Println(set, "±", test.element)
+	}
+
+	// Only the untouched element remains
+	if set.Size() != 1 || !set.Has(5) || set.Has(100000) {
+		t.Fatalf("set.Size() - unexpected result: %s", set)
+	}
+}
+
+// TestIntSetAlgebra verifies that the IntSet set algebra methods are working properly
+func TestIntSetAlgebra(t *testing.T) {
+	log.Println("TestIntSetAlgebra()")
+
+	// Create sets with elements spread across several words
+	set := NewInt(1, 3, 5, 70, 200)
+	other := NewInt(1, 2, 6, 200, 300)
+
+	// Create a table of tests and expected results of set algebra
+	var tests = []struct {
+		name   string
+		result *IntSet
+		target *IntSet
+	}{
+		{"Union", set.Union(other), NewInt(1, 2, 3, 5, 6, 70, 200, 300)},
+		{"Intersection", set.Intersection(other), NewInt(1, 200)},
+		{"Difference", set.Difference(other), NewInt(3, 5, 70)},
+		{"Difference", other.Difference(set), NewInt(2, 6, 300)},
+		{"SymmetricDifference", set.SymmetricDifference(other), NewInt(2, 3, 5, 6, 70, 300)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+
+		log.Println(test.name, "(", set, ",", other, ") =", test.result)
+	}
+
+	// Verify subsets, and equality of sets with different numbers of words
+	if !set.Subset(NewInt(1, 200)) || set.Subset(other) || !set.Subset(NewInt()) {
+		t.Fatalf("set.Subset() - unexpected result")
+	}
+	trimmed := NewInt(1, 500)
+	trimmed.Remove(500)
+	if !trimmed.Equal(NewInt(1)) || !NewInt(1).Equal(trimmed) {
+		t.Fatalf("set.Equal() - unexpected result for trailing empty words")
+	}
+	if size := set.Size(); size != 5 {
+		t.Fatalf("set.Size() - unexpected result: %d", size)
+	}
+}
+
+// TestIntSetConversion verifies that sets may be converted between Set and IntSet
+func TestIntSetConversion(t *testing.T) {
+	log.Println("TestIntSetConversion()")
+
+	// Convert a set of integers into an IntSet, and back again
+	set, err := IntSetFrom(New(1, 3, 5, 100))
+	if err != nil {
+		t.Fatalf("IntSetFrom() - unexpected error: %v", err)
+	}
+	if !set.ToSet().Equal(New(1, 3, 5, 100)) {
+		t.Fatalf("set.ToSet() - unexpected result: %s", set.ToSet())
+	}
+
+	// Enumeration is in ascending order
+	values := set.Enumerate()
+	for i, v := range []int{1, 3, 5, 100} {
+		if values[i] != v {
+			t.Fatalf("set.Enumerate() - unexpected element at %d: %d", i, values[i])
+		}
+	}
+
+	// Non-integer and negative elements cannot be converted
+	if _, err := IntSetFrom(New(1, "2")); err == nil {
+		t.Fatalf("IntSetFrom() - expected error for non-integer element")
+	}
+	if _, err := IntSetFrom(New(1, -2)); err == nil {
+		t.Fatalf("IntSetFrom() - expected error for negative element")
+	}
+	if _, err := IntSetFrom(New(1, math.MaxInt)); err == nil {
+		t.Fatalf("IntSetFrom() - expected error for element greater than MaxIntSetValue")
+	}
+}
@@ -0,0 +1,267 @@
+package set
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// NumberMode determines the Go type of numbers decoded from JSON into a Set
+type NumberMode int
+
+const (
+	// NumberInt decodes integral numbers as int, and all other numbers as float64
+	NumberInt NumberMode = iota
+	// NumberInt64 decodes integral numbers as int64, and all other numbers as float64
+	NumberInt64
+	// NumberFloat64 decodes all numbers as float64
+	NumberFloat64
+	// NumberJSON decodes all numbers as json.Number, preserving their exact text
+	NumberJSON
+)
+
+// JSONOptions configures how a Set is encoded and decoded as JSON
+type JSONOptions struct {
+	// Sorted orders elements in the canonical order used by String and MarshalText, so that encoded
+	// output is stable
+	Sorted bool
+	// Number determines the Go type of decoded numbers
+	Number NumberMode
+}
+
+// JSONOptionsProvider supplies the JSONOptions used by a JSONSet.  Implementations are usually empty
+// structs, so that the options are fixed by the type of a JSONSet, rather than by its value.
+type JSONOptionsProvider interface {
+	JSONOptions() JSONOptions
+}
+
+// JSONSet wraps a Set, encoding and decoding it as JSON using the options supplied by the zero value of
+// O.  Unlike SetJSONOptions, the options are part of the type, so they also apply to sets which are
+// allocated by encoding/json while decoding, such as fields of a request or response struct:
+//
+//	type sortedInt64 struct{}
+//
+//	func (sortedInt64) JSONOptions() set.JSONOptions {
+//		return set.JSONOptions{Sorted: true, Number: set.NumberInt64}
+//	}
+//
+//	type request struct {
+//		IDs set.JSONSet[sortedInt64] `json:"ids"`
+//	}
+//
+// A JSONSet whose Set is nil encodes as null, and decoding into it allocates a new Set.
+type JSONSet[O JSONOptionsProvider] struct {
+	*Set
+}
+
+// MarshalJSON implements json.Marshaler, encoding the wrapped set using the options supplied by O
+func (s JSONSet[O]) MarshalJSON() ([]byte, error) {
+	if s.Set == nil {
+		return []byte("null"), nil
+	}
+
+	var o O
+	return marshalJSONSet(s.Set, o.JSONOptions())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding into the wrapped set using the options supplied by
+// O, and allocating the set if it is nil.  The options are also stored on the set, so that it encodes
+// in the same way if it is later used without the wrapper.
+func (s *JSONSet[O]) UnmarshalJSON(b []byte) error {
+	if s.Set == nil {
+		s.Set = New()
+	}
+
+	var o O
+	s.Set.SetJSONOptions(o.JSONOptions())
+	return s.Set.UnmarshalJSON(b)
+}
+
+// SetJSONOptions sets the options used when encoding and decoding the set as JSON.  Nested sets are
+// encoded and decoded using the same options.  The options belong to this set value, so a set which is
+// allocated by encoding/json while decoding always uses the zero options; use JSONSet in that case.
+func (s *Set) SetJSONOptions(opts JSONOptions) {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.jsonOpts = opts
+}
+
+// MarshalJSON implements json.Marshaler, encoding the set as a JSON array of its elements.  Nested
+// sets are encoded as nested arrays, and Pair elements as objects with X and Y fields.
+func (s *Set) MarshalJSON() ([]byte, error) {
+	// Lock set for read
+	s.mutex.RLock()
+	opts := s.jsonOpts
+	s.mutex.RUnlock()
+
+	return marshalJSONSet(s, opts)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a JSON array into the set, and replacing its
+// contents.  Nested arrays are decoded as nested sets, and objects with exactly X and Y fields are
+// decoded as Pair elements.  Numbers are decoded according to the set's JSONOptions.
+func (s *Set) UnmarshalJSON(b []byte) error {
+	// Lock set for read
+	s.mutex.RLock()
+	opts := s.jsonOpts
+	s.mutex.RUnlock()
+
+	// Decode all elements into a new map
+	values, err := unmarshalJSONValues(b, opts)
+	if err != nil {
+		return err
+	}
+
+	m := make(map[interface{}]struct{}, len(values))
+	for _, v := range values {
+		m[v] = struct{}{}
+	}
+
+	// Lock set for write, and replace its contents
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replace(m)
+	return nil
+}
+
+// marshalJSONSet encodes a set as a JSON array, using the specified options for it and all nested sets
+func marshalJSONSet(s *Set, opts JSONOptions) ([]byte, error) {
+	return marshalJSONValues(s.Enumerate(), opts)
+}
+
+// marshalJSONValues encodes a slice of elements as a JSON array, using the specified options for it and
+// all nested sets.  If the options request sorting, the slice is sorted in place.
+func marshalJSONValues(values []interface{}, opts JSONOptions) ([]byte, error) {
+	// Sort elements into canonical order, if requested
+	if opts.Sorted {
+		SortElements(values)
+	}
+
+	// Encode all elements
+	elements := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b, err := encodeJSONElement(v, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, b)
+	}
+
+	return append(append([]byte{'['}, bytes.Join(elements, []byte{','})...), ']'), nil
+}
+
+// unmarshalJSONValues decodes a JSON array into a slice of elements, using the specified options for
+// all nested sets
+func unmarshalJSONValues(b []byte, opts JSONOptions) ([]interface{}, error) {
+	// Decode the array, preserving the text of numbers for conversion
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var values []interface{}
+	if err := d.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	// Convert all elements
+	for i, v := range values {
+		e, err := decodeJSONElement(v, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = e
+	}
+
+	return values, nil
+}
+
+// encodeJSONElement encodes a single element of a set as JSON
+func encodeJSONElement(v interface{}, opts JSONOptions) ([]byte, error) {
+	switch e := v.(type) {
+	case *Set:
+		return marshalJSONSet(e, opts)
+	case Pair:
+		x, err := encodeJSONElement(e.X, opts)
+		if err != nil {
+			return nil, err
+		}
+		y, err := encodeJSONElement(e.Y, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(fmt.Sprintf(`{"X":%s,"Y":%s}`, x, y)), nil
+	default:
+		return json.Marshal(v)
+	}
+}
+
+// decodeJSONElement converts a single value decoded from JSON into an element of a set
+func decodeJSONElement(v interface{}, opts JSONOptions) (interface{}, error) {
+	switch e := v.(type) {
+	case []interface{}:
+		// Arrays are nested sets
+		nested := New()
+		nested.jsonOpts = opts
+		for _, n := range e {
+			ne, err := decodeJSONElement(n, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			nested.m[ne] = struct{}{}
+		}
+
+		return nested, nil
+	case map[string]interface{}:
+		// Objects with exactly X and Y fields are pairs
+		x, okX := e["X"]
+		y, okY := e["Y"]
+		if !okX || !okY || len(e) != 2 {
+			return nil, fmt.Errorf("set: cannot decode JSON object as set element: %v", e)
+		}
+
+		px, err := decodeJSONElement(x, opts)
+		if err != nil {
+			return nil, err
+		}
+		py, err := decodeJSONElement(y, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return Pair{X: px, Y: py}, nil
+	case json.Number:
+		return decodeJSONNumber(e, opts.Number)
+	default:
+		// Strings, booleans and null need no conversion
+		return v, nil
+	}
+}
+
+// decodeJSONNumber converts a JSON number into the Go type chosen by the number mode
+func decodeJSONNumber(n json.Number, mode NumberMode) (interface{}, error) {
+	switch mode {
+	case NumberJSON:
+		return n, nil
+	case NumberFloat64:
+		return n.Float64()
+	}
+
+	// Decode integral numbers as integers, if they fit
+	if i, err := n.Int64(); err == nil {
+		if mode == NumberInt64 {
+			return i, nil
+		}
+		if i >= math.MinInt && i <= math.MaxInt {
+			return int(i), nil
+		}
+	}
+
+	return n.Float64()
+}
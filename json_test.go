@@ -0,0 +1,186 @@
+package set
+
+import (
+	"encoding/json"
+	"log"
+	"testing"
+)
+
+// TestMarshalJSON verifies that the set.MarshalJSON() method is working properly
+func TestMarshalJSON(t *testing.T) {
+	log.Println("TestMarshalJSON()")
+
+	// Create a table of tests and expected results of sorted JSON encoding
+	var tests = []struct {
+		source *Set
+		result string
+	}{
+		// Empty set
+		{New(), `[]`},
+		// Numbers, ordered by value rather than by their encoding
+		{New(3, 1, 2), `[1,2,3]`},
+		{New(10, 2, 1, 100), `[1,2,10,100]`},
+		{New(-5.0, -10.0, 0.5, 1e3), `[-10,-5,0.5,1000]`},
+		// Mixed types, grouped by type
+		{New("b", true, 1.5, nil), `[null,true,1.5,"b"]`},
+		{New("b", "a", "B"), `["B","a","b"]`},
+		// Pairs
+		{New(Pair{1, "a"}), `[{"X":1,"Y":"a"}]`},
+		// Nested sets
+		{New(New(2, 1), 0), `[[1,2],0]`},
+		{New(New(20, 3)), `[[3,20]]`},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		test.source.SetJSONOptions(JSONOptions{Sorted: true})
+
+		b, err := json.Marshal(test.source)
+		if err != nil {
+			t.Fatalf("json.Marshal() - unexpected error: %v", err)
+		}
+		if string(b) != test.result {
+			t.Fatalf("json.Marshal() - unexpected result: %s != %s", b, test.result)
+		}
+
+		log.Println(test.source, "->", string(b))
+	}
+}
+
+// TestUnmarshalJSON verifies that the set.UnmarshalJSON() method is working properly
+func TestUnmarshalJSON(t *testing.T) {
+	log.Println("TestUnmarshalJSON()")
+
+	// Create a table of tests and expected results of JSON decoding with each number mode
+	var tests = []struct {
+		mode   NumberMode
+		source string
+		target *Set
+	}{
+		// Integers by default
+		{NumberInt, `[1,2,3,1]`, New(1, 2, 3)},
+		// Non-integral numbers
+		{NumberInt, `[1,2.5]`, New(1, 2.5)},
+		// 64-bit integers
+		{NumberInt64, `[1,2.5]`, New(int64(1), 2.5)},
+		// Floating point numbers
+		{NumberFloat64, `[1,2.5]`, New(1.0, 2.5)},
+		// Exact numbers
+		{NumberJSON, `[1,2.50]`, New(json.Number("1"), json.Number("2.50"))},
+		// Other types
+		{NumberInt, `["a",true,null]`, New("a", true, nil)},
+		// Pairs
+		{NumberInt, `[{"X":1,"Y":{"X":"a","Y":false}}]`, New(Pair{1, Pair{"a", false}})},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		set := New(42)
+		set.SetJSONOptions(JSONOptions{Number: test.mode})
+
+		if err := json.Unmarshal([]byte(test.source), set); err != nil {
+			t.Fatalf("json.Unmarshal() - unexpected error: %v", err)
+		}
+		if !set.Equal(test.target) {
+			t.Fatalf("json.Unmarshal() - sets not equal: %s != %s", set, test.target)
+		}
+
+		log.Println(test.source, "->", set)
+	}
+
+	// Invalid input is rejected
+	for _, s := range []string{`{}`, `[{"X":1}]`, `[1,`} {
+		if err := json.Unmarshal([]byte(s), New()); err == nil {
+			t.Fatalf("json.Unmarshal(%s) - expected error", s)
+		}
+	}
+}
+
+// TestJSONRoundTrip verifies that a set embedded in a struct round trips through JSON
+func TestJSONRoundTrip(t *testing.T) {
+	log.Println("TestJSONRoundTrip()")
+
+	// Create a struct which holds sets, including a nested set
+	type request struct {
+		IDs  *Set `json:"ids"`
+		Tags *Set `json:"tags"`
+	}
+
+	in := request{
+		IDs:  New(1, 2, 3, Pair{4, 5}),
+		Tags: New("a", New("b", "c")),
+	}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() - unexpected error: %v", err)
+	}
+
+	// Decode into zero value sets
+	var out request
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal() - unexpected error: %v", err)
+	}
+
+	// Integers are members of the set after a round trip
+	if !out.IDs.Equal(in.IDs) || !out.IDs.Has(1) || !out.IDs.Has(Pair{4, 5}) {
+		t.Fatalf("json.Unmarshal() - sets not equal: %s != %s", out.IDs, in.IDs)
+	}
+
+	// Nested sets are decoded as sets with the same elements
+	if out.Tags.Size() != 2 || !out.Tags.Has("a") {
+		t.Fatalf("json.Unmarshal() - unexpected result: %s", out.Tags)
+	}
+	for _, e := range out.Tags.Enumerate() {
+		if nested, ok := e.(*Set); ok && !nested.Equal(New("b", "c")) {
+			t.Fatalf("json.Unmarshal() - unexpected nested set: %s", nested)
+		}
+	}
+
+	log.Println(string(b))
+}
+
+// sortedInt64 supplies JSONOptions for JSONSet, sorting elements and decoding numbers as int64
+type sortedInt64 struct{}
+
+// JSONOptions implements JSONOptionsProvider
+func (sortedInt64) JSONOptions() JSONOptions {
+	return JSONOptions{Sorted: true, Number: NumberInt64}
+}
+
+// TestJSONSet verifies that a JSONSet applies its options to sets allocated while decoding a struct
+func TestJSONSet(t *testing.T) {
+	log.Println("TestJSONSet()")
+
+	type response struct {
+		IDs   JSONSet[sortedInt64]  `json:"ids"`
+		Other *JSONSet[sortedInt64] `json:"other"`
+		Empty JSONSet[sortedInt64]  `json:"empty"`
+	}
+
+	// Decode into zero values, which are allocated using the options of the type
+	var out response
+	if err := json.Unmarshal([]byte(`{"ids":[10,2,1],"other":[3],"empty":null}`), &out); err != nil {
+		t.Fatalf("json.Unmarshal() - unexpected error: %v", err)
+	}
+	if !out.IDs.Equal(New(int64(1), int64(2), int64(10))) || !out.Other.Has(int64(3)) {
+		t.Fatalf("json.Unmarshal() - unexpected result: %s, %s", out.IDs, out.Other)
+	}
+
+	// Encoding sorts elements, and the options stay with the decoded set
+	b, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("json.Marshal() - unexpected error: %v", err)
+	}
+	if string(b) != `{"ids":[1,2,10],"other":[3],"empty":[]}` {
+		t.Fatalf("json.Marshal() - unexpected result: %s", b)
+	}
+	if b, _ := json.Marshal(out.IDs.Set); string(b) != `[1,2,10]` {
+		t.Fatalf("json.Marshal() - unexpected result without wrapper: %s", b)
+	}
+
+	// A nil set encodes as null
+	if b, _ := json.Marshal(JSONSet[sortedInt64]{}); string(b) != `null` {
+		t.Fatalf("json.Marshal() - unexpected result for nil set: %s", b)
+	}
+}
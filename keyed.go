@@ -0,0 +1,326 @@
+package set
+
+import (
+	"iter"
+	"sync"
+)
+
+// KeyedSet represents an unordered collection of values, where membership is determined by a key
+// derived from each value, rather than by the value itself.  For example, a set keyed by
+// strings.ToLower holds hostnames case-insensitively.  The first value inserted for each key is kept,
+// and is the value returned by Enumerate and the other methods of the set.
+//
+// The key function must return values which can be used as map keys.  Set algebra between two
+// KeyedSets compares their keys directly, so it is only meaningful when both sets use the same key
+// function.  A KeyedSet is safe for concurrent use.
+type KeyedSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Function which derives the key of each value
+	key func(interface{}) interface{}
+	// First value inserted for each key
+	m map[interface{}]interface{}
+}
+
+// NewKeyed creates a new KeyedSet which uses the specified key function, optionally adding initial
+// elements to the set
+func NewKeyed(key func(interface{}) interface{}, values ...interface{}) *KeyedSet {
+	// Initialize set
+	s := KeyedSet{
+		key: key,
+		m:   make(map[interface{}]interface{}, len(values)),
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.add(v)
+	}
+
+	return &s
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if an element with the same key already existed, in which case the existing element is kept
+func (s *KeyedSet) Add(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.add(value)
+}
+
+// All returns an iterator over all elements in the set, in no particular order, for use with a
+// range loop.  The set is locked for read while iteration is in progress, so the loop body must not
+// modify the set.
+func (s *KeyedSet) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		// Lock set for read
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+
+		for _, v := range s.m {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Clone copies the current set into a new, identical set
+func (s *KeyedSet) Clone() *KeyedSet {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy set into a new set
+	outSet := s.empty(len(s.m))
+	for k, v := range s.m {
+		outSet.m[k] = v
+	}
+
+	return outSet
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// whose keys are present in the parameter set
+func (s *KeyedSet) Difference(t *KeyedSet) *KeyedSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set of differences between the sets
+	diffSet := s.empty(0)
+	for k, v := range s.m {
+		if _, ok := t.m[k]; !ok {
+			diffSet.m[k] = v
+		}
+	}
+
+	return diffSet
+}
+
+// Enumerate returns an unordered slice of all elements in the set, each of which is the first value
+// inserted for its key
+func (s *KeyedSet) Enumerate() []interface{} {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice
+	values := make([]interface{}, 0, len(s.m))
+	for _, v := range s.m {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets contain elements with exactly the same keys
+func (s *KeyedSet) Equal(t *KeyedSet) bool {
+	return s.Size() == t.Size() && s.Subset(t)
+}
+
+// Filter applies a function over all elements of the set, and returns all elements which return true
+// when the function is applied.  The function is applied to a snapshot of the set, without holding any
+// lock, so it may safely access or modify the set.
+func (s *KeyedSet) Filter(fn func(interface{}) bool) *KeyedSet {
+	// Lock set for read, and gather all keys and values
+	s.mutex.RLock()
+	keys := make([]interface{}, 0, len(s.m))
+	values := make([]interface{}, 0, len(s.m))
+	for k, v := range s.m {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	s.mutex.RUnlock()
+
+	// Create a set to return with elements which match filter function
+	filterSet := s.empty(0)
+	for i, v := range values {
+		if fn(v) {
+			filterSet.m[keys[i]] = v
+		}
+	}
+
+	return filterSet
+}
+
+// Get returns the element of the set with the same key as a value, which is the first value inserted
+// with that key, and whether or not such an element exists
+func (s *KeyedSet) Get(value interface{}) (interface{}, bool) {
+	k := s.key(value)
+
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	v, ok := s.m[k]
+	return v, ok
+}
+
+// Has checks for membership of an element with the same key as a value in the set
+func (s *KeyedSet) Has(value interface{}) bool {
+	_, ok := s.Get(value)
+	return ok
+}
+
+// Intersection returns a set containing all elements of this set whose keys are also present in the
+// parameter set.  Elements of the new set are taken from this set.
+func (s *KeyedSet) Intersection(t *KeyedSet) *KeyedSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set of intersections between the sets
+	intSet := s.empty(0)
+	for k, v := range s.m {
+		if _, ok := t.m[k]; ok {
+			intSet.m[k] = v
+		}
+	}
+
+	return intSet
+}
+
+// Keys returns an unordered slice of the keys of all elements in the set
+func (s *KeyedSet) Keys() []interface{} {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all keys into a slice
+	keys := make([]interface{}, 0, len(s.m))
+	for k := range s.m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Remove destroys the element with the same key as a value, returning true if the element was
+// destroyed, or false if it did not exist
+func (s *KeyedSet) Remove(value interface{}) bool {
+	k := s.key(value)
+
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence, remove value from set
+	if _, ok := s.m[k]; !ok {
+		return false
+	}
+
+	delete(s.m, k)
+	return true
+}
+
+// Size returns the size or cardinality of this set
+func (s *KeyedSet) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.m)
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *KeyedSet) String() string {
+	return formatValues(s.Enumerate())
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if
+// every key of the parameter set is present in this set, or false if it is not
+func (s *KeyedSet) Subset(t *KeyedSet) bool {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Check that every key of the parameter set is present
+	for k := range t.m {
+		if _, ok := s.m[k]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SymmetricDifference returns a set containing all elements whose keys are present in only one of
+// this set and the parameter set
+func (s *KeyedSet) SymmetricDifference(t *KeyedSet) *KeyedSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set of elements present in only one of the sets
+	symSet := s.empty(0)
+	for k, v := range s.m {
+		if _, ok := t.m[k]; !ok {
+			symSet.m[k] = v
+		}
+	}
+	for k, v := range t.m {
+		if _, ok := s.m[k]; !ok {
+			symSet.m[k] = v
+		}
+	}
+
+	return symSet
+}
+
+// ToSet copies the elements of the current set into a new Set, discarding their keys, and returning an
+// error if any element cannot be used as an element of a Set.  Elements which have distinct keys in this
+// set, but are equal using ==, become a single element of the new set.
+func (s *KeyedSet) ToSet() (*Set, error) {
+	outSet := New()
+	for _, v := range s.Enumerate() {
+		if _, err := outSet.TryAdd(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return outSet, nil
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements of the
+// parameter set whose keys are not present in this set.  Where both sets hold an element with the
+// same key, the element from this set is kept.
+func (s *KeyedSet) Union(t *KeyedSet) *KeyedSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Copy the parameter set, then overwrite it with this set, so that this set's elements are kept
+	unionSet := s.empty(len(s.m) + len(t.m))
+	for k, v := range t.m {
+		unionSet.m[k] = v
+	}
+	for k, v := range s.m {
+		unionSet.m[k] = v
+	}
+
+	return unionSet
+}
+
+// add inserts a value into the set if no value with the same key exists, returning true if it was
+// newly added.  The caller must hold the write lock.
+func (s *KeyedSet) add(value interface{}) bool {
+	// Check existence of the key
+	k := s.key(value)
+	if _, ok := s.m[k]; ok {
+		return false
+	}
+
+	// Add value to set
+	s.m[k] = value
+	return true
+}
+
+// empty creates a new, empty set with the same key function as this set
+func (s *KeyedSet) empty(size int) *KeyedSet {
+	return &KeyedSet{
+		key: s.key,
+		m:   make(map[interface{}]interface{}, size),
+	}
+}
@@ -0,0 +1,150 @@
+package set
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lowerKey keys string elements case-insensitively, for use with NewKeyed
+func lowerKey(v interface{}) interface{} {
+	return strings.ToLower(v.(string))
+}
+
+// TestKeyedSet verifies that the basic methods of a KeyedSet are working properly
+func TestKeyedSet(t *testing.T) {
+	log.Println("TestKeyedSet()")
+
+	s := NewKeyed(lowerKey, "Example.com", "example.COM", "golang.org")
+	if s.Size() != 2 {
+		t.Fatalf("set.NewKeyed() - unexpected result: %s", s)
+	}
+	if !s.Add("GoDoc.org") || s.Add("GOLANG.ORG") || s.Size() != 3 {
+		t.Fatalf("set.KeyedSet.Add() - unexpected result: %s", s)
+	}
+	if !s.Has("EXAMPLE.com") || s.Has("example.net") {
+		t.Fatalf("set.KeyedSet.Has() - unexpected result")
+	}
+
+	// The first inserted original is kept
+	if v, ok := s.Get("EXAMPLE.COM"); !ok || v != "Example.com" {
+		t.Fatalf("set.KeyedSet.Get() - unexpected result: %v, %v", v, ok)
+	}
+	if _, ok := s.Get("example.net"); ok {
+		t.Fatalf("set.KeyedSet.Get() - unexpected result for missing element")
+	}
+	if s.String() != "{ Example.com GoDoc.org golang.org }" {
+		t.Fatalf("set.KeyedSet.String() - unexpected result: %s", s)
+	}
+	if set, err := s.ToSet(); err != nil || !set.Equal(New("Example.com", "GoDoc.org", "golang.org")) {
+		t.Fatalf("set.KeyedSet.ToSet() - unexpected result: %s, %v", set, err)
+	}
+	if keys := New(s.Keys()...); !keys.Equal(New("example.com", "godoc.org", "golang.org")) {
+		t.Fatalf("set.KeyedSet.Keys() - unexpected result: %s", keys)
+	}
+
+	if !s.Remove("godoc.ORG") || s.Remove("godoc.org") || s.Size() != 2 {
+		t.Fatalf("set.KeyedSet.Remove() - unexpected result: %s", s)
+	}
+
+	// After removal, a new original may be inserted
+	if !s.Add("GODOC.ORG") {
+		t.Fatalf("set.KeyedSet.Add() - unexpected result after Remove(): %s", s)
+	}
+	if v, _ := s.Get("godoc.org"); v != "GODOC.ORG" {
+		t.Fatalf("set.KeyedSet.Get() - unexpected result after Remove(): %v", v)
+	}
+
+	// Clones are independent of the original
+	c := s.Clone()
+	c.Add("example.net")
+	if s.Has("example.net") || c.Size() != 4 {
+		t.Fatalf("set.KeyedSet.Clone() - unexpected result: %s", c)
+	}
+
+	n := 0
+	for v := range s.All() {
+		if !s.Has(v) {
+			t.Fatalf("set.KeyedSet.All() - unexpected element: %v", v)
+		}
+		n++
+	}
+	if n != s.Size() || len(s.Enumerate()) != s.Size() {
+		t.Fatalf("set.KeyedSet.All() - unexpected result: %d elements", n)
+	}
+
+	f := s.Filter(func(v interface{}) bool {
+		return strings.HasSuffix(v.(string), ".org") || strings.HasSuffix(v.(string), ".ORG")
+	})
+	if !f.Equal(NewKeyed(lowerKey, "golang.org", "godoc.org")) || !f.Has("GoLang.Org") {
+		t.Fatalf("set.KeyedSet.Filter() - unexpected result: %s", f)
+	}
+
+	// Paths are compared after cleaning
+	p := NewKeyed(func(v interface{}) interface{} {
+		return filepath.Clean(v.(string))
+	}, "a/b/../c", "a/c", "./a//c/")
+	if p.Size() != 1 || !p.Has("a/./c") || p.Enumerate()[0] != "a/b/../c" {
+		t.Fatalf("set.KeyedSet - unexpected result with filepath.Clean: %s", p)
+	}
+
+	// Elements need not be comparable, provided that their keys are
+	b := NewKeyed(func(v interface{}) interface{} {
+		return string(v.([]byte))
+	}, []byte("b"), []byte("a"), []byte("b"))
+	if b.Size() != 2 || b.String() != "{ [97] [98] }" {
+		t.Fatalf("set.KeyedSet.String() - unexpected result with slices: %s", b)
+	}
+	if _, err := b.ToSet(); err == nil {
+		t.Fatalf("set.KeyedSet.ToSet() - expected error for unhashable values")
+	}
+
+	// The filter function may modify the set
+	f = s.Filter(func(v interface{}) bool {
+		return s.Add(strings.ToUpper(v.(string)) + ".NET")
+	})
+	if f.Size() != 3 || s.Size() != 6 {
+		t.Fatalf("set.KeyedSet.Filter() - unexpected result when modifying set: %s", f)
+	}
+}
+
+// TestKeyedSetAlgebra verifies that set algebra between KeyedSets compares elements by key
+func TestKeyedSetAlgebra(t *testing.T) {
+	log.Println("TestKeyedSetAlgebra()")
+
+	s := NewKeyed(lowerKey, "A", "B", "C")
+	u := NewKeyed(lowerKey, "b", "c", "d")
+
+	var tests = []struct {
+		name   string
+		result *KeyedSet
+		set    *Set
+	}{
+		{"Union", s.Union(u), New("A", "B", "C", "d")},
+		{"Intersection", s.Intersection(u), New("B", "C")},
+		{"Intersection", u.Intersection(s), New("b", "c")},
+		{"Difference", s.Difference(u), New("A")},
+		{"SymmetricDifference", s.SymmetricDifference(u), New("A", "d")},
+		{"Union", s.Union(s), New("A", "B", "C")},
+		{"Difference", s.Difference(s), New()},
+	}
+
+	for _, test := range tests {
+		if set, _ := test.result.ToSet(); !set.Equal(test.set) {
+			t.Fatalf("set.KeyedSet.%s() - unexpected result: %s != %s", test.name, test.result, test.set)
+		}
+	}
+
+	// Results use the same key function
+	if !s.Union(u).Has("D") || s.Difference(u).Has("b") {
+		t.Fatalf("set.KeyedSet - unexpected key function in result")
+	}
+
+	if !s.Subset(NewKeyed(lowerKey, "a", "c")) || s.Subset(u) || !s.Subset(s) {
+		t.Fatalf("set.KeyedSet.Subset() - unexpected result")
+	}
+	if !s.Equal(NewKeyed(lowerKey, "a", "b", "c")) || s.Equal(u) {
+		t.Fatalf("set.KeyedSet.Equal() - unexpected result")
+	}
+}
@@ -0,0 +1,201 @@
+package set
+
+import (
+	"fmt"
+	"sync"
+)
+
+// EventKind identifies the kind of change to a set described by an Event
+type EventKind int
+
+const (
+	// EventAdd indicates that an element was added to the set
+	EventAdd EventKind = iota
+	// EventRemove indicates that an element was removed from the set
+	EventRemove
+)
+
+// String returns a string representation of this event kind
+func (k EventKind) String() string {
+	switch k {
+	case EventAdd:
+		return "add"
+	case EventRemove:
+		return "remove"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event describes a single change to the membership of a set
+type Event struct {
+	// Kind of change
+	Kind EventKind
+	// Element which was added or removed
+	Value interface{}
+}
+
+// BackPressure determines what happens when an event is sent to a subscription channel which is full
+type BackPressure int
+
+const (
+	// Block waits for the subscriber to receive the event, so that no events are lost.  The set
+	// remains locked for write until the event is received, so a slow subscriber slows all writers.
+	Block BackPressure = iota
+	// DropNewest discards the event being sent, so that writers are never delayed
+	DropNewest
+	// DropOldest discards the oldest buffered event to make room for the event being sent, so that
+	// writers are never delayed, and the subscriber always sees the most recent changes
+	DropOldest
+)
+
+// subscription is a single subscriber to changes in a set
+type subscription struct {
+	// Callback which receives each event, under the set's write lock
+	fn func(Event)
+	// Channel which receives each event, if the subscriber uses a channel
+	ch chan Event
+	// Closed when the subscriber unsubscribes, to release a blocked sender
+	done chan struct{}
+}
+
+// Subscribe registers a callback which receives an Event for every element added to or removed from
+// the set, including elements replaced when the set is decoded.  Events are delivered while the set is
+// locked for write, in exactly the order in which the changes were made, so the callback must not call
+// any method of the set, and should return quickly.  The returned function unsubscribes the callback,
+// and may be called more than once.
+func (s *Set) Subscribe(fn func(Event)) (unsubscribe func()) {
+	return subscribe(&s.mutex, &s.subs, &subscription{
+		fn: fn,
+	})
+}
+
+// SubscribeChan returns a channel which receives an Event for every element added to or removed from
+// the set, in exactly the order in which the changes were made.  The channel is buffered to hold the
+// specified number of events, and the back-pressure policy determines what happens when the buffer is
+// full.  The returned function unsubscribes the channel and closes it, and may be called more than once.
+func (s *Set) SubscribeChan(buffer int, policy BackPressure) (<-chan Event, func()) {
+	sub := newChanSubscription(buffer, policy)
+	return sub.ch, subscribe(&s.mutex, &s.subs, sub)
+}
+
+// newChanSubscription creates a subscription which sends each event to a buffered channel, applying
+// the back-pressure policy when the buffer is full
+func newChanSubscription(buffer int, policy BackPressure) *subscription {
+	sub := &subscription{
+		ch:   make(chan Event, buffer),
+		done: make(chan struct{}),
+	}
+
+	sub.fn = func(e Event) {
+		switch policy {
+		case DropNewest:
+			select {
+			case sub.ch <- e:
+			default:
+			}
+		case DropOldest:
+			// Keep discarding buffered events until there is room, as the subscriber may receive
+			// concurrently.  An unbuffered channel has nothing to discard.
+			for {
+				select {
+				case sub.ch <- e:
+					return
+				default:
+				}
+
+				if cap(sub.ch) == 0 {
+					return
+				}
+
+				select {
+				case <-sub.ch:
+				default:
+				}
+			}
+		default:
+			select {
+			case sub.ch <- e:
+			case <-sub.done:
+			}
+		}
+	}
+
+	return sub
+}
+
+// subscribe adds a subscription to the list of subscribers of a set, which is guarded by the set's
+// mutex, returning a function which removes it
+func subscribe(mutex *sync.RWMutex, subs *[]*subscription, sub *subscription) func() {
+	// Lock set for write
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	*subs = append(*subs, sub)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			// Release any sender blocked on this subscription before waiting for the lock
+			if sub.done != nil {
+				close(sub.done)
+			}
+
+			// Lock set for write
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			// Remove the subscription, copying the slice so that it is never modified in place
+			remaining := make([]*subscription, 0, len(*subs))
+			for _, t := range *subs {
+				if t != sub {
+					remaining = append(remaining, t)
+				}
+			}
+			*subs = remaining
+
+			if sub.ch != nil {
+				close(sub.ch)
+			}
+		})
+	}
+}
+
+// notify delivers an event to all subscribers.  The caller must hold the write lock.
+func (s *Set) notify(kind EventKind, value interface{}) {
+	notifyAll(s.subs, kind, value)
+}
+
+// notifyAll delivers an event to every subscription in a list.  The caller must hold the write lock of
+// the set which owns the list.
+func notifyAll(subs []*subscription, kind EventKind, value interface{}) {
+	for _, sub := range subs {
+		sub.fn(Event{
+			Kind:  kind,
+			Value: value,
+		})
+	}
+}
+
+// replace replaces the contents of the set with a new map, notifying subscribers of each element which
+// was removed or added.  The caller must hold the write lock.
+func (s *Set) replace(m map[interface{}]struct{}) {
+	old := s.m
+	s.m = m
+
+	// Avoid comparing the maps when nobody is listening
+	if len(s.subs) == 0 {
+		return
+	}
+
+	for k := range old {
+		if _, ok := m[k]; !ok {
+			s.notify(EventRemove, k)
+		}
+	}
+	for k := range m {
+		if _, ok := old[k]; !ok {
+			s.notify(EventAdd, k)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package set
+
+import (
+	"log"
+	"sync"
+	"testing"
+)
+
+// TestSubscribe verifies that the set.Subscribe() method is working properly
+func TestSubscribe(t *testing.T) {
+	log.Println("TestSubscribe()")
+
+	s := New(1)
+	var events []Event
+	unsubscribe := s.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	// Only changes to membership produce events
+	s.Add(2)
+	s.Add(2)
+	s.Remove(1)
+	s.Remove(3)
+	s.AddAll(3, 4)
+	s.RemoveAll(4)
+	s.Swap(2, 5)
+
+	expected := []Event{
+		{EventAdd, 2},
+		{EventRemove, 1},
+		{EventAdd, 3},
+		{EventAdd, 4},
+		{EventRemove, 4},
+		{EventRemove, 2},
+		{EventAdd, 5},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("set.Subscribe() - unexpected events: %v != %v", events, expected)
+	}
+	for i := range events {
+		if events[i] != expected[i] {
+			t.Fatalf("set.Subscribe() - unexpected events: %v != %v", events, expected)
+		}
+	}
+
+	// Decoding the set produces events for each change
+	events = nil
+	if err := s.UnmarshalText([]byte("{ 3 6 }")); err != nil {
+		t.Fatalf("set.UnmarshalText() - unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0] != (Event{EventRemove, 5}) || events[1] != (Event{EventAdd, 6}) {
+		t.Fatalf("set.UnmarshalText() - unexpected events: %v", events)
+	}
+
+	// No events are delivered after unsubscribing
+	events = nil
+	unsubscribe()
+	unsubscribe()
+	s.Add(7)
+	if len(events) != 0 {
+		t.Fatalf("set.Subscribe() - unexpected events after unsubscribe: %v", events)
+	}
+}
+
+// TestSubscribeChan verifies that the set.SubscribeChan() method applies each back-pressure policy
+func TestSubscribeChan(t *testing.T) {
+	log.Println("TestSubscribeChan()")
+
+	// Create a table of policies and the values expected to be received from a full channel
+	var tests = []struct {
+		policy BackPressure
+		values []interface{}
+	}{
+		{DropNewest, []interface{}{1, 2}},
+		{DropOldest, []interface{}{4, 5}},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		s := New()
+		ch, unsubscribe := s.SubscribeChan(2, test.policy)
+		s.AddAll(1, 2, 3, 4, 5)
+		unsubscribe()
+
+		var values []interface{}
+		for e := range ch {
+			values = append(values, e.Value)
+		}
+
+		if len(values) != len(test.values) || values[0] != test.values[0] || values[1] != test.values[1] {
+			t.Fatalf("set.SubscribeChan(%d) - unexpected values: %v != %v", test.policy, values, test.values)
+		}
+	}
+
+	// Dropping events from an unbuffered channel never blocks
+	s := New()
+	_, unsubscribe := s.SubscribeChan(0, DropOldest)
+	s.Add(1)
+	unsubscribe()
+}
+
+// TestSubscribeChanBlock verifies that the Block policy delivers all events in order
+func TestSubscribeChanBlock(t *testing.T) {
+	log.Println("TestSubscribeChanBlock()")
+
+	s := New()
+	ch, unsubscribe := s.SubscribeChan(0, Block)
+
+	// Mutate the set from many goroutines, while receiving every event
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				s.Add(i*100 + j)
+				s.Remove(i*100 + j)
+			}
+		}(i)
+	}
+
+	// Every add must be followed by its remove, and membership must always be consistent
+	members := make(map[interface{}]bool)
+	for n := 0; n < 400; n++ {
+		e := <-ch
+		if members[e.Value] == (e.Kind == EventAdd) {
+			t.Fatalf("set.SubscribeChan() - out of order event: %v %v", e.Kind, e.Value)
+		}
+		members[e.Value] = e.Kind == EventAdd
+	}
+	wg.Wait()
+
+	// Unsubscribing releases a blocked writer
+	done := make(chan struct{})
+	go func() {
+		s.Add(-1)
+		close(done)
+	}()
+	unsubscribe()
+	<-done
+}
@@ -0,0 +1,380 @@
+package set
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+)
+
+// OrderedSet represents a collection of unique values which preserves the order in which elements
+// were first added to the set.  Add, Remove, At and IndexOf all take logarithmic time or better.
+type OrderedSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Elements of the set in insertion order, with removed elements left as tombstones until the
+	// slice is compacted
+	values []interface{}
+	// Map of elements to their slot in the values slice
+	index map[interface{}]int
+	// Fenwick tree counting the elements which are not tombstones, so that the position of a slot
+	// can be found without scanning the slice.  Node k covers the slots from k&(k+1) to k inclusive.
+	live []int
+	// Number of tombstones in the values slice
+	removed int
+	// Whether or not re-adding an existing element moves it to the back of the set
+	moveToBack bool
+}
+
+// orderedTombstone marks the slot of an element which has been removed from an OrderedSet.  It is
+// unexported, so it can never be an element of a set.
+type orderedTombstone struct{}
+
+// NewOrdered creates a new OrderedSet, and initializes its internal map, optionally adding initial
+// elements to the set in the order they are specified
+func NewOrdered(values ...interface{}) *OrderedSet {
+	// Initialize set
+	s := OrderedSet{
+		values: make([]interface{}, 0, len(values)),
+		index:  make(map[interface{}]int),
+		live:   make([]int, 0, len(values)),
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// Add inserts a new element at the back of the set, returning true if the element was newly added,
+// or false if it already existed.  If SetMoveToBack is enabled, an existing element is moved to the
+// back of the set.
+func (s *OrderedSet) Add(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence, optionally moving the element to the back
+	if i, ok := s.index[value]; ok {
+		if s.moveToBack {
+			s.removeAt(i)
+			s.push(value)
+		}
+
+		return false
+	}
+
+	// Add value to set
+	s.push(value)
+	return true
+}
+
+// At returns the element at the zero-based position i in insertion order, and whether or not such
+// an element exists
+func (s *OrderedSet) At(i int) (interface{}, bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check bounds
+	if i < 0 || i >= len(s.index) {
+		return nil, false
+	}
+
+	return s.values[s.slot(i)], true
+}
+
+// Clone copies the current set into a new, identical set, preserving order and options
+func (s *OrderedSet) Clone() *OrderedSet {
+	// Copy set into a new set
+	outSet := NewOrdered(s.Enumerate()...)
+
+	s.mutex.RLock()
+	outSet.moveToBack = s.moveToBack
+	s.mutex.RUnlock()
+
+	return outSet
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set, in the order of this set
+func (s *OrderedSet) Difference(t *OrderedSet) *OrderedSet {
+	return s.Filter(func(v interface{}) bool {
+		return !t.Has(v)
+	})
+}
+
+// Enumerate returns a slice of all elements in the set, in insertion order
+func (s *OrderedSet) Enumerate() []interface{} {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy all values into a new slice, skipping tombstones
+	values := make([]interface{}, 0, len(s.index))
+	for _, v := range s.values {
+		if v != (orderedTombstone{}) {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets contain the same elements, regardless of their order
+func (s *OrderedSet) Equal(t *OrderedSet) bool {
+	return s.Size() == t.Size() && s.Difference(t).Size() == 0
+}
+
+// Filter applies a function over all elements of the set, and returns all elements which return true
+// when the function is applied, in the order of this set
+func (s *OrderedSet) Filter(fn func(interface{}) bool) *OrderedSet {
+	// Create a set to return with elements which match filter function
+	filterSet := NewOrdered()
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, add elements which it matches
+		if fn(e) {
+			filterSet.Add(e)
+		}
+	}
+
+	return filterSet
+}
+
+// Has checks for membership of an element in the set
+func (s *OrderedSet) Has(value interface{}) bool {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.index[value]
+	return ok
+}
+
+// IndexOf returns the zero-based position of an element in insertion order, or -1 if the element
+// is not present in the set
+func (s *OrderedSet) IndexOf(value interface{}) int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if i, ok := s.index[value]; ok {
+		return s.position(i)
+	}
+
+	return -1
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter
+// set, in the order of this set
+func (s *OrderedSet) Intersection(t *OrderedSet) *OrderedSet {
+	return s.Filter(t.Has)
+}
+
+// Map applies a function over all elements of the set, and returns the resulting set, in the order
+// the results were first produced
+func (s *OrderedSet) Map(fn func(interface{}) interface{}) *OrderedSet {
+	// Create a set to return with function applied
+	mapSet := NewOrdered()
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, capture result
+		mapSet.Add(fn(e))
+	}
+
+	return mapSet
+}
+
+// Reduce applies a function over all elements of the set in insertion order, accumulating the results
+// into a final result value
+func (s *OrderedSet) Reduce(value interface{}, fn func(interface{}, interface{}) interface{}) interface{} {
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		value = fn(value, e)
+	}
+
+	return value
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist.
+// The order of the remaining elements is preserved.
+func (s *OrderedSet) Remove(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence
+	i, ok := s.index[value]
+	if !ok {
+		return false
+	}
+
+	// Remove value from set, leaving the order of the remaining elements intact
+	s.removeAt(i)
+	return true
+}
+
+// SetMoveToBack sets whether or not adding an element which already exists in the set moves it to
+// the back of the set.  By default, elements keep the position at which they were first added.
+func (s *OrderedSet) SetMoveToBack(enabled bool) {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.moveToBack = enabled
+}
+
+// Size returns the size or cardinality of this set
+func (s *OrderedSet) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.index)
+}
+
+// String returns a string representation of this set, with elements in insertion order
+func (s *OrderedSet) String() string {
+	// Print identifier
+	str := "{ "
+
+	// Check for empty set, print symbol if empty
+	values := s.Enumerate()
+	if len(values) == 0 {
+		return str + "Ø }"
+	}
+
+	// Print all elements
+	for _, v := range values {
+		// Print pairs separately
+		if pair, ok := v.(Pair); ok {
+			str = str + fmt.Sprintf("%v ", pair.String())
+		} else {
+			str = str + fmt.Sprintf("%v ", v)
+		}
+	}
+
+	return str + "}"
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *OrderedSet) Subset(t *OrderedSet) bool {
+	// Check if all elements in the parameter set are contained within the set
+	for _, v := range t.Enumerate() {
+		// Check if element is contained, if not, return false
+		if !s.Has(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set, with elements of this set first
+func (s *OrderedSet) SymmetricDifference(t *OrderedSet) *OrderedSet {
+	return s.Difference(t).Union(t.Difference(s))
+}
+
+// ToSet copies the elements of the current set into a new, unordered Set
+func (s *OrderedSet) ToSet() *Set {
+	return New(s.Enumerate()...)
+}
+
+// Union returns a set containing all elements present in this set, followed by all elements present
+// in the parameter set which were not already present
+func (s *OrderedSet) Union(t *OrderedSet) *OrderedSet {
+	// Copy the current set into a new set
+	outSet := NewOrdered(s.Enumerate()...)
+
+	// Enumerate and add all elements from the parameter set
+	for _, e := range t.Enumerate() {
+		outSet.Add(e)
+	}
+
+	return outSet
+}
+
+// compact removes all tombstones from the values slice, and rebuilds the index and tree.  The caller
+// must hold the write lock.
+func (s *OrderedSet) compact() {
+	values := make([]interface{}, 0, len(s.index))
+	for _, v := range s.values {
+		if v != (orderedTombstone{}) {
+			s.index[v] = len(values)
+			values = append(values, v)
+		}
+	}
+
+	// Every slot is live, so build the tree by adding each node's count into its parent
+	live := make([]int, len(values))
+	for k := range live {
+		live[k]++
+		if p := k | (k + 1); p < len(live) {
+			live[p] += live[k]
+		}
+	}
+
+	s.values = values
+	s.live = live
+	s.removed = 0
+}
+
+// position returns the number of elements before a slot, which is its position in insertion order.
+// The caller must hold the lock for read.
+func (s *OrderedSet) position(slot int) int {
+	n := 0
+	for k := slot - 1; k >= 0; k = k&(k+1) - 1 {
+		n += s.live[k]
+	}
+
+	return n
+}
+
+// push appends a value to the back of the set.  The caller must hold the write lock.
+func (s *OrderedSet) push(value interface{}) {
+	slot := len(s.values)
+	s.index[value] = slot
+	s.values = append(s.values, value)
+
+	// The new node covers the earlier slots from slot&(slot+1), as well as its own
+	s.live = append(s.live, s.position(slot)-s.position(slot&(slot+1))+1)
+}
+
+// removeAt replaces the value at a slot with a tombstone, compacting the set once at least half of
+// its slots are tombstones.  The caller must hold the write lock.
+func (s *OrderedSet) removeAt(slot int) {
+	delete(s.index, s.values[slot])
+	s.values[slot] = orderedTombstone{}
+	s.removed++
+
+	for k := slot; k < len(s.live); k = k | (k + 1) {
+		s.live[k]--
+	}
+
+	if s.removed >= len(s.values)/2 {
+		s.compact()
+	}
+}
+
+// slot returns the slot of the element at position i in insertion order.  The caller must hold the
+// lock for read, and i must be a valid position.
+func (s *OrderedSet) slot(i int) int {
+	// Descend the tree, skipping whole nodes which end before the element.  With this layout, the
+	// node ending at slot+step-1 covers exactly the step slots after slot, when slot is a multiple
+	// of step.
+	slot := 0
+	for step := 1 << (bits.Len(uint(len(s.live))) - 1); step > 0; step >>= 1 {
+		if k := slot + step - 1; k < len(s.live) && s.live[k] <= i {
+			slot += step
+			i -= s.live[k]
+		}
+	}
+
+	return slot
+}
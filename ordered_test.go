@@ -0,0 +1,171 @@
+package set
+
+import (
+	"log"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// orderedEqual checks if a slice of elements matches the expected elements, in order
+func orderedEqual(values []interface{}, expected ...interface{}) bool {
+	if len(values) != len(expected) {
+		return false
+	}
+
+	for i := range values {
+		if values[i] != expected[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestOrderedAddRemove verifies that the OrderedSet.Add() and OrderedSet.Remove() methods preserve
+// insertion order
+func TestOrderedAddRemove(t *testing.T) {
+	log.Println("TestOrderedAddRemove()")
+
+	// Create a set, add some initial values, including duplicates
+	set := NewOrdered("b", "c", "a", "c", "b")
+	if !orderedEqual(set.Enumerate(), "b", "c", "a") {
+		t.Fatalf("NewOrdered() - unexpected order: %s", set)
+	}
+
+	// Re-adding an element keeps its position
+	if set.Add("b") {
+		t.Fatalf("set.Add(b) - unexpected result: true")
+	}
+	if !orderedEqual(set.Enumerate(), "b", "c", "a") {
+		t.Fatalf("set.Add(b) - unexpected order: %s", set)
+	}
+
+	// Removing an element preserves the order of the remaining elements
+	if !set.Remove("c") || set.Remove("c") {
+		t.Fatalf("set.Remove(c) - unexpected result")
+	}
+	if !orderedEqual(set.Enumerate(), "b", "a") || set.IndexOf("a") != 1 {
+		t.Fatalf("set.Remove(c) - unexpected order: %s", set)
+	}
+
+	// With move to back enabled, re-adding an element moves it to the back
+	set.SetMoveToBack(true)
+	set.Add("d")
+	set.Add("b")
+	if !orderedEqual(set.Enumerate(), "a", "d", "b") {
+		t.Fatalf("set.Add(b) - unexpected order with move to back: %s", set)
+	}
+
+	log.Println(set)
+}
+
+// TestOrderedPosition verifies that the OrderedSet.At() and OrderedSet.IndexOf() methods are working properly
+func TestOrderedPosition(t *testing.T) {
+	log.Println("TestOrderedPosition()")
+
+	// Create a set, add some initial values
+	set := NewOrdered(5, 3, 1)
+
+	// Create a table of tests and expected results for positional access
+	var tests = []struct {
+		index   int
+		element interface{}
+		ok      bool
+	}{
+		{0, 5, true},
+		{1, 3, true},
+		{2, 1, true},
+		{3, nil, false},
+		{-1, nil, false},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		e, ok := set.At(test.index)
+		if e != test.element || ok != test.ok {
+			t.Fatalf("set.At(%d) - unexpected result: %v, %t", test.index, e, ok)
+		}
+
+		if ok && set.IndexOf(e) != test.index {
+			t.Fatalf("set.IndexOf(%v) - unexpected result: %d", e, set.IndexOf(e))
+		}
+	}
+
+	if set.IndexOf(2) != -1 {
+		t.Fatalf("set.IndexOf(2) - unexpected result: %d", set.IndexOf(2))
+	}
+}
+
+// TestOrderedPositionRemove verifies that the OrderedSet.At() and OrderedSet.IndexOf() methods remain
+// correct as many elements are added, removed and moved to the back
+func TestOrderedPositionRemove(t *testing.T) {
+	log.Println("TestOrderedPositionRemove()")
+
+	// Compare the set against a plain slice, using a fixed seed so that failures are reproducible
+	rng := rand.New(rand.NewSource(1))
+	set := NewOrdered()
+	set.SetMoveToBack(true)
+	var expected []interface{}
+	for i := 0; i < 5000; i++ {
+		v := rng.Intn(200)
+		if rng.Intn(3) == 0 {
+			set.Remove(v)
+			expected = slices.DeleteFunc(expected, func(e interface{}) bool {
+				return e == v
+			})
+		} else {
+			set.Add(v)
+			expected = append(slices.DeleteFunc(expected, func(e interface{}) bool {
+				return e == v
+			}), v)
+		}
+
+		if !orderedEqual(set.Enumerate(), expected...) || set.Size() != len(expected) {
+			t.Fatalf("set.Enumerate() - unexpected order after %d operations: %s", i, set)
+		}
+
+		j := rng.Intn(len(expected) + 1)
+		if e, ok := set.At(j); j < len(expected) && (e != expected[j] || set.IndexOf(e) != j) {
+			t.Fatalf("set.At(%d) - unexpected result: %v, %t", j, e, ok)
+		} else if j == len(expected) && ok {
+			t.Fatalf("set.At(%d) - unexpected result beyond end: %v", j, e)
+		}
+	}
+}
+
+// TestOrderedAlgebra verifies that the OrderedSet set algebra methods preserve order
+func TestOrderedAlgebra(t *testing.T) {
+	log.Println("TestOrderedAlgebra()")
+
+	// Create a set, add some initial values
+	set := NewOrdered(5, 3, 1)
+	other := NewOrdered(6, 1, 2)
+
+	// Create a table of tests and expected results of set algebra
+	var tests = []struct {
+		name     string
+		result   *OrderedSet
+		expected []interface{}
+	}{
+		{"Union", set.Union(other), []interface{}{5, 3, 1, 6, 2}},
+		{"Intersection", set.Intersection(other), []interface{}{1}},
+		{"Difference", set.Difference(other), []interface{}{5, 3}},
+		{"SymmetricDifference", set.SymmetricDifference(other), []interface{}{5, 3, 6, 2}},
+		{"Filter", set.Filter(func(v interface{}) bool { return v.(int) > 1 }), []interface{}{5, 3}},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !orderedEqual(test.result.Enumerate(), test.expected...) {
+			t.Fatalf("set.%s() - unexpected result: %s", test.name, test.result)
+		}
+
+		log.Println(test.name, "(", set, ",", other, ") =", test.result)
+	}
+
+	// Equality does not depend on order
+	if !set.Equal(NewOrdered(1, 3, 5)) || !set.ToSet().Equal(New(1, 3, 5)) {
+		t.Fatalf("set.Equal() - unexpected result")
+	}
+}
@@ -0,0 +1,698 @@
+package set
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+)
+
+// hamtBits is the number of hash bits consumed at each level of a PersistentSet's trie
+const hamtBits = 5
+
+// persistentSeed is shared by all persistent sets, so that equal elements occupy the same position in
+// every trie, and set algebra can compare and reuse subtrees directly
+var persistentSeed = maphash.MakeSeed()
+
+// PersistentSet represents an immutable, unordered collection of unique values, backed by a hash array
+// mapped trie.  Methods which would modify a Set instead return a new PersistentSet, which shares all
+// unchanged parts of its trie with the original, so that each new version costs memory proportional to
+// the depth of the trie, rather than the size of the set.
+//
+// Set algebra exploits this sharing: subtrees which are shared between two sets are reused or skipped
+// without being visited, so operations on closely related versions of a set are fast.  As a
+// PersistentSet can never change, it is safe for concurrent use without locking.  The zero value is an
+// empty set.
+type PersistentSet struct {
+	// Root of the trie, or nil for the empty set
+	root *hamtNode
+}
+
+// hamtNode is an interior node of the trie.  Each node holds at least two elements beneath it, except
+// for the root, which may hold one.
+type hamtNode struct {
+	// Bitmap of which of the 32 possible children are present.  Unused by collision nodes, which occur
+	// once every bit of the hash has been consumed, and hold only leaves, in no particular order.
+	bitmap uint32
+	// Number of elements beneath this node
+	size int
+	// Present children, in order of their position in the bitmap
+	entries []hamtEntry
+}
+
+// hamtEntry is a child of a trie node, which is either a nested node, or a leaf holding a single element
+type hamtEntry struct {
+	// Nested node, or nil if this entry is a leaf
+	node *hamtNode
+	// Hash of the element held by a leaf
+	hash uint64
+	// Element held by a leaf
+	value interface{}
+}
+
+// NewPersistent creates a new PersistentSet, optionally containing initial elements
+func NewPersistent(values ...interface{}) *PersistentSet {
+	// Hash all values into leaves, and build the trie from them at once
+	leaves := make([]hamtEntry, len(values))
+	for i, v := range values {
+		leaves[i] = hamtLeaf(v)
+	}
+
+	return &PersistentSet{
+		root: hamtRoot(hamtBuild(leaves, 0)),
+	}
+}
+
+// PersistentSetFrom creates a new PersistentSet containing the elements of a Set
+func PersistentSetFrom(t *Set) *PersistentSet {
+	return NewPersistent(t.Enumerate()...)
+}
+
+// All returns an iterator over all elements in the set, in no particular order, for use with a range loop
+func (s *PersistentSet) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		if s.root != nil {
+			s.root.each(yield)
+		}
+	}
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *PersistentSet) Difference(t *PersistentSet) *PersistentSet {
+	if s.root == nil || t.root == nil {
+		return s
+	}
+
+	return &PersistentSet{
+		root: hamtRoot(hamtDifference(hamtEntry{node: s.root}, hamtEntry{node: t.root}, 0)),
+	}
+}
+
+// Enumerate returns an unordered slice of all elements in the set
+func (s *PersistentSet) Enumerate() []interface{} {
+	values := make([]interface{}, 0, s.Size())
+	for v := range s.All() {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets contain exactly the same elements
+func (s *PersistentSet) Equal(t *PersistentSet) bool {
+	return s.Size() == t.Size() && s.Subset(t)
+}
+
+// Has checks for membership of an element in the set
+func (s *PersistentSet) Has(value interface{}) bool {
+	if s.root == nil {
+		return false
+	}
+
+	return s.root.has(hamtLeaf(value), 0)
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *PersistentSet) Intersection(t *PersistentSet) *PersistentSet {
+	if s.root == nil {
+		return s
+	}
+	if t.root == nil {
+		return t
+	}
+
+	return &PersistentSet{
+		root: hamtRoot(hamtIntersection(hamtEntry{node: s.root}, hamtEntry{node: t.root}, 0)),
+	}
+}
+
+// Size returns the size or cardinality of this set
+func (s *PersistentSet) Size() int {
+	if s.root == nil {
+		return 0
+	}
+
+	return s.root.size
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *PersistentSet) String() string {
+	return s.ToSet().String()
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *PersistentSet) Subset(t *PersistentSet) bool {
+	if t.root == nil {
+		return true
+	}
+	if s.root == nil {
+		return false
+	}
+
+	return hamtSubset(hamtEntry{node: s.root}, hamtEntry{node: t.root}, 0)
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *PersistentSet) SymmetricDifference(t *PersistentSet) *PersistentSet {
+	return s.Union(t).Difference(s.Intersection(t))
+}
+
+// ToSet copies the elements of the current set into a new Set
+func (s *PersistentSet) ToSet() *Set {
+	// Copy set into a new set
+	outSet := &Set{
+		m: make(map[interface{}]struct{}, s.Size()),
+	}
+	for v := range s.All() {
+		outSet.m[v] = struct{}{}
+	}
+
+	return outSet
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *PersistentSet) Union(t *PersistentSet) *PersistentSet {
+	if s.root == nil {
+		return t
+	}
+	if t.root == nil {
+		return s
+	}
+
+	return &PersistentSet{
+		root: hamtRoot(hamtUnion(hamtEntry{node: s.root}, hamtEntry{node: t.root}, 0)),
+	}
+}
+
+// With returns a set containing all elements of this set, as well as the specified element.  If the
+// element is already present, the current set is returned.
+func (s *PersistentSet) With(value interface{}) *PersistentSet {
+	leaf := hamtLeaf(value)
+	if s.root == nil {
+		return &PersistentSet{
+			root: hamtRoot(leaf, true),
+		}
+	}
+
+	node, added := s.root.with(leaf, 0)
+	if !added {
+		return s
+	}
+
+	return &PersistentSet{
+		root: node,
+	}
+}
+
+// Without returns a set containing all elements of this set, except for the specified element.  If
+// the element is not present, the current set is returned.
+func (s *PersistentSet) Without(value interface{}) *PersistentSet {
+	if s.root == nil {
+		return s
+	}
+
+	e, ok, removed := s.root.without(hamtLeaf(value), 0)
+	if !removed {
+		return s
+	}
+
+	return &PersistentSet{
+		root: hamtRoot(e, ok),
+	}
+}
+
+// hamtLeaf creates a leaf holding a single element
+func hamtLeaf(value interface{}) hamtEntry {
+	return hamtEntry{
+		hash:  maphash.Comparable(persistentSeed, value),
+		value: value,
+	}
+}
+
+// hamtRoot converts the result of a trie operation into a root node, which may hold a single leaf, or
+// nil if the result is empty
+func hamtRoot(e hamtEntry, ok bool) *hamtNode {
+	if !ok {
+		return nil
+	}
+	if e.node != nil {
+		return e.node
+	}
+
+	return &hamtNode{
+		bitmap:  uint32(1) << hamtIndex(e.hash, 0),
+		size:    1,
+		entries: []hamtEntry{e},
+	}
+}
+
+// hamtIndex returns the index of the child which holds a hash at the level with the specified shift
+func hamtIndex(hash uint64, shift uint) uint {
+	return uint(hash>>shift) & (1<<hamtBits - 1)
+}
+
+// hamtCollision returns whether or not nodes at the specified shift are collision nodes
+func hamtCollision(shift uint) bool {
+	return shift >= 64
+}
+
+// size returns the number of elements held by an entry
+func (e hamtEntry) size() int {
+	if e.node != nil {
+		return e.node.size
+	}
+
+	return 1
+}
+
+// same returns whether or not two leaves hold the same element
+func (e hamtEntry) same(f hamtEntry) bool {
+	return e.hash == f.hash && e.value == f.value
+}
+
+// hamtBuild builds a trie from a list of leaves which share the same hash prefix down to the specified
+// shift, discarding duplicates.  It returns false if there are no leaves.
+func hamtBuild(leaves []hamtEntry, shift uint) (hamtEntry, bool) {
+	switch len(leaves) {
+	case 0:
+		return hamtEntry{}, false
+	case 1:
+		return leaves[0], true
+	}
+
+	// Once all hash bits are used, the remaining leaves share a collision node
+	if hamtCollision(shift) {
+		n := &hamtNode{}
+		for _, l := range leaves {
+			if !n.hasLeaf(l) {
+				n.entries = append(n.entries, l)
+			}
+		}
+		return hamtNormalize(n)
+	}
+
+	// Partition leaves by their index at this level, and build each partition
+	var buckets [1 << hamtBits][]hamtEntry
+	for _, l := range leaves {
+		i := hamtIndex(l.hash, shift)
+		buckets[i] = append(buckets[i], l)
+	}
+
+	n := &hamtNode{}
+	for i, b := range buckets {
+		if e, ok := hamtBuild(b, shift+hamtBits); ok {
+			n.bitmap |= uint32(1) << uint(i)
+			n.entries = append(n.entries, e)
+		}
+	}
+
+	return hamtNormalize(n)
+}
+
+// hamtNormalize computes the size of a newly built node, and returns it as an entry.  A node which holds
+// a single leaf is replaced by the leaf, and an empty node returns false.
+func hamtNormalize(n *hamtNode) (hamtEntry, bool) {
+	n.size = 0
+	for _, e := range n.entries {
+		n.size += e.size()
+	}
+
+	switch {
+	case n.size == 0:
+		return hamtEntry{}, false
+	case n.size == 1:
+		return n.entries[0], true
+	default:
+		return hamtEntry{node: n}, true
+	}
+}
+
+// hamtPair builds the smallest trie holding two different leaves, which share the same hash prefix
+// down to the specified shift
+func hamtPair(a hamtEntry, b hamtEntry, shift uint) hamtEntry {
+	e, _ := hamtBuild([]hamtEntry{a, b}, shift)
+	return e
+}
+
+// each calls a function for every element beneath the node, stopping early if it returns false
+func (n *hamtNode) each(yield func(interface{}) bool) bool {
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.each(yield) {
+				return false
+			}
+		} else if !yield(e.value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// child returns the position of the child which would hold a hash at the level with the specified
+// shift, and whether or not that child is present
+func (n *hamtNode) child(hash uint64, shift uint) (int, bool) {
+	return n.at(uint32(1) << hamtIndex(hash, shift))
+}
+
+// at returns the position of the child with the specified bit in the bitmap, and whether or not that
+// child is present
+func (n *hamtNode) at(bit uint32) (int, bool) {
+	return bits.OnesCount32(n.bitmap & (bit - 1)), n.bitmap&bit != 0
+}
+
+// has checks whether or not the element held by a leaf is beneath the node
+func (n *hamtNode) has(leaf hamtEntry, shift uint) bool {
+	if hamtCollision(shift) {
+		return n.hasLeaf(leaf)
+	}
+
+	i, ok := n.child(leaf.hash, shift)
+	if !ok {
+		return false
+	}
+	if e := n.entries[i]; e.node != nil {
+		return e.node.has(leaf, shift+hamtBits)
+	} else {
+		return e.same(leaf)
+	}
+}
+
+// hasLeaf checks whether or not a leaf is held directly by a collision node
+func (n *hamtNode) hasLeaf(leaf hamtEntry) bool {
+	for _, e := range n.entries {
+		if e.same(leaf) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// with returns a copy of the node with a leaf added, sharing all unchanged children, and whether or
+// not the leaf was added.  If the leaf is already present, the node itself is returned.
+func (n *hamtNode) with(leaf hamtEntry, shift uint) (*hamtNode, bool) {
+	// Collision nodes simply append the leaf
+	if hamtCollision(shift) {
+		if n.hasLeaf(leaf) {
+			return n, false
+		}
+
+		return n.replace(len(n.entries), 0, leaf), true
+	}
+
+	// Insert a new child if none exists at this index
+	i, ok := n.child(leaf.hash, shift)
+	if !ok {
+		c := n.replace(i, 0, leaf)
+		c.bitmap |= uint32(1) << hamtIndex(leaf.hash, shift)
+		return c, true
+	}
+
+	// Descend into a nested node, or split a leaf into a nested node
+	e := n.entries[i]
+	switch {
+	case e.node != nil:
+		child, added := e.node.with(leaf, shift+hamtBits)
+		if !added {
+			return n, false
+		}
+		return n.replace(i, 1, hamtEntry{node: child}), true
+	case e.same(leaf):
+		return n, false
+	default:
+		return n.replace(i, 1, hamtPair(e, leaf, shift+hamtBits)), true
+	}
+}
+
+// without returns the result of removing a leaf from the node, sharing all unchanged children, whether
+// or not the result is non-empty, and whether or not the leaf was removed.  If the leaf is not present,
+// the node itself is returned.
+func (n *hamtNode) without(leaf hamtEntry, shift uint) (hamtEntry, bool, bool) {
+	// Find the position of the leaf within the node
+	i := -1
+	if hamtCollision(shift) {
+		for j, e := range n.entries {
+			if e.same(leaf) {
+				i = j
+				break
+			}
+		}
+	} else if j, ok := n.child(leaf.hash, shift); ok {
+		i = j
+	}
+	if i < 0 {
+		return hamtEntry{node: n}, true, false
+	}
+
+	// Descend into a nested node
+	e := n.entries[i]
+	if e.node != nil {
+		child, ok, removed := e.node.without(leaf, shift+hamtBits)
+		if !removed {
+			return hamtEntry{node: n}, true, false
+		}
+		if ok {
+			c, ok := hamtNormalize(n.replace(i, 1, child))
+			return c, ok, true
+		}
+	} else if !e.same(leaf) {
+		return hamtEntry{node: n}, true, false
+	}
+
+	// Remove the child entirely
+	c := n.replace(i, 1)
+	if !hamtCollision(shift) {
+		c.bitmap &^= uint32(1) << hamtIndex(leaf.hash, shift)
+	}
+	r, ok := hamtNormalize(c)
+	return r, ok, true
+}
+
+// replace returns a copy of the node, with count entries at position i replaced by the specified
+// entries, and its size updated.  The bitmap is copied unchanged.
+func (n *hamtNode) replace(i int, count int, entries ...hamtEntry) *hamtNode {
+	c := &hamtNode{
+		bitmap:  n.bitmap,
+		size:    n.size,
+		entries: make([]hamtEntry, 0, len(n.entries)-count+len(entries)),
+	}
+
+	c.entries = append(c.entries, n.entries[:i]...)
+	c.entries = append(c.entries, entries...)
+	c.entries = append(c.entries, n.entries[i+count:]...)
+
+	for _, e := range n.entries[i : i+count] {
+		c.size -= e.size()
+	}
+	for _, e := range entries {
+		c.size += e.size()
+	}
+
+	return c
+}
+
+// hamtUnion returns the union of two non-empty entries at the same position of their tries
+func hamtUnion(a hamtEntry, b hamtEntry, shift uint) (hamtEntry, bool) {
+	switch {
+	case a.node != nil && a.node == b.node:
+		return a, true
+	case b.node == nil:
+		if a.node == nil {
+			if a.same(b) {
+				return a, true
+			}
+			return hamtPair(a, b, shift), true
+		}
+		n, _ := a.node.with(b, shift)
+		return hamtEntry{node: n}, true
+	case a.node == nil:
+		n, _ := b.node.with(a, shift)
+		return hamtEntry{node: n}, true
+	}
+
+	// Union of two collision nodes
+	if hamtCollision(shift) {
+		n := a.node
+		for _, e := range b.node.entries {
+			n, _ = n.with(e, shift)
+		}
+		return hamtEntry{node: n}, true
+	}
+
+	// Merge children present in either node
+	n := &hamtNode{
+		bitmap: a.node.bitmap | b.node.bitmap,
+	}
+	for bm := n.bitmap; bm != 0; bm &= bm - 1 {
+		bit := bm & -bm
+		ai, aok := a.node.at(bit)
+		bi, bok := b.node.at(bit)
+
+		switch {
+		case aok && bok:
+			e, _ := hamtUnion(a.node.entries[ai], b.node.entries[bi], shift+hamtBits)
+			n.entries = append(n.entries, e)
+		case aok:
+			n.entries = append(n.entries, a.node.entries[ai])
+		default:
+			n.entries = append(n.entries, b.node.entries[bi])
+		}
+	}
+
+	// Reuse either input if the union adds nothing to it
+	e, _ := hamtNormalize(n)
+	switch e.size() {
+	case a.size():
+		return a, true
+	case b.size():
+		return b, true
+	}
+	return e, true
+}
+
+// hamtIntersection returns the intersection of two non-empty entries at the same position of their
+// tries, and whether or not it is non-empty
+func hamtIntersection(a hamtEntry, b hamtEntry, shift uint) (hamtEntry, bool) {
+	switch {
+	case a.node != nil && a.node == b.node:
+		return a, true
+	case a.node == nil:
+		return a, hamtHas(b, a, shift)
+	case b.node == nil:
+		return b, a.node.has(b, shift)
+	}
+
+	// Intersection of two collision nodes
+	n := &hamtNode{}
+	if hamtCollision(shift) {
+		for _, e := range a.node.entries {
+			if b.node.hasLeaf(e) {
+				n.entries = append(n.entries, e)
+			}
+		}
+	} else {
+		// Intersect children present in both nodes
+		for bm := a.node.bitmap & b.node.bitmap; bm != 0; bm &= bm - 1 {
+			bit := bm & -bm
+			ai, _ := a.node.at(bit)
+			bi, _ := b.node.at(bit)
+
+			if e, ok := hamtIntersection(a.node.entries[ai], b.node.entries[bi], shift+hamtBits); ok {
+				n.bitmap |= bit
+				n.entries = append(n.entries, e)
+			}
+		}
+	}
+
+	// Reuse either input if the intersection removes nothing from it
+	e, ok := hamtNormalize(n)
+	if !ok {
+		return e, false
+	}
+	switch e.size() {
+	case a.size():
+		return a, true
+	case b.size():
+		return b, true
+	}
+	return e, true
+}
+
+// hamtDifference returns the elements of a non-empty entry which are not in another non-empty entry at
+// the same position of their tries, and whether or not the result is non-empty
+func hamtDifference(a hamtEntry, b hamtEntry, shift uint) (hamtEntry, bool) {
+	switch {
+	case a.node != nil && a.node == b.node:
+		return hamtEntry{}, false
+	case a.node == nil:
+		return a, !hamtHas(b, a, shift)
+	case b.node == nil:
+		e, ok, _ := a.node.without(b, shift)
+		return e, ok
+	}
+
+	n := &hamtNode{}
+	if hamtCollision(shift) {
+		// Keep leaves of the collision node which are not in the other
+		for _, e := range a.node.entries {
+			if !b.node.hasLeaf(e) {
+				n.entries = append(n.entries, e)
+			}
+		}
+	} else {
+		// Keep children only present in the first node, and subtract those present in both
+		for bm := a.node.bitmap; bm != 0; bm &= bm - 1 {
+			bit := bm & -bm
+			ai, _ := a.node.at(bit)
+			bi, bok := b.node.at(bit)
+
+			e, ok := a.node.entries[ai], true
+			if bok {
+				e, ok = hamtDifference(e, b.node.entries[bi], shift+hamtBits)
+			}
+			if ok {
+				n.bitmap |= bit
+				n.entries = append(n.entries, e)
+			}
+		}
+	}
+
+	// Reuse the input if the difference removes nothing from it
+	e, ok := hamtNormalize(n)
+	if ok && e.size() == a.size() {
+		return a, true
+	}
+	return e, ok
+}
+
+// hamtSubset determines if every element of entry b is also in entry a, at the same position of
+// their tries
+func hamtSubset(a hamtEntry, b hamtEntry, shift uint) bool {
+	switch {
+	case a.node != nil && a.node == b.node:
+		return true
+	case b.node == nil:
+		return hamtHas(a, b, shift)
+	case a.node == nil || b.node.size > a.node.size:
+		return false
+	}
+
+	// Every leaf of a collision node must be in the other
+	if hamtCollision(shift) {
+		for _, e := range b.node.entries {
+			if !a.node.hasLeaf(e) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Every child of b must be present in a, and a superset of the child of b
+	if b.node.bitmap&^a.node.bitmap != 0 {
+		return false
+	}
+	for bm := b.node.bitmap; bm != 0; bm &= bm - 1 {
+		bit := bm & -bm
+		ai, _ := a.node.at(bit)
+		bi, _ := b.node.at(bit)
+
+		if !hamtSubset(a.node.entries[ai], b.node.entries[bi], shift+hamtBits) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hamtHas checks whether or not the element held by a leaf is in an entry at the same position
+func hamtHas(e hamtEntry, leaf hamtEntry, shift uint) bool {
+	if e.node == nil {
+		return e.same(leaf)
+	}
+
+	return e.node.has(leaf, shift)
+}
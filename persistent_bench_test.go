@@ -0,0 +1,84 @@
+package set
+
+import (
+	"testing"
+)
+
+// persistentBenchValues returns a slice of n distinct values for benchmarks
+func persistentBenchValues(n int) []interface{} {
+	values := make([]interface{}, n)
+	for i := range values {
+		values[i] = i
+	}
+
+	return values
+}
+
+// benchmarkSnapshotClone checks the performance of taking a snapshot of a Set after each change, using
+// the set.Clone() method
+func benchmarkSnapshotClone(n int, size int) {
+	s := New(persistentBenchValues(size)...)
+
+	// Change one element, then snapshot the set, n times
+	for i := 0; i < n; i++ {
+		s.Add(size + i)
+		_ = s.Clone()
+	}
+}
+
+// benchmarkSnapshotPersistent checks the performance of taking a snapshot of a PersistentSet after
+// each change, using the persistentset.With() method
+func benchmarkSnapshotPersistent(n int, size int) {
+	s := NewPersistent(persistentBenchValues(size)...)
+
+	// Each new version is itself a snapshot
+	for i := 0; i < n; i++ {
+		s = s.With(size + i)
+	}
+}
+
+// BenchmarkSnapshotCloneSmall checks the performance of snapshots using set.Clone() over a small data set
+func BenchmarkSnapshotCloneSmall(b *testing.B) {
+	benchmarkSnapshotClone(b.N, 10)
+}
+
+// BenchmarkSnapshotCloneLarge checks the performance of snapshots using set.Clone() over a large data set
+func BenchmarkSnapshotCloneLarge(b *testing.B) {
+	benchmarkSnapshotClone(b.N, 10000)
+}
+
+// BenchmarkSnapshotPersistentSmall checks the performance of snapshots using persistentset.With() over
+// a small data set
+func BenchmarkSnapshotPersistentSmall(b *testing.B) {
+	benchmarkSnapshotPersistent(b.N, 10)
+}
+
+// BenchmarkSnapshotPersistentLarge checks the performance of snapshots using persistentset.With() over
+// a large data set
+func BenchmarkSnapshotPersistentLarge(b *testing.B) {
+	benchmarkSnapshotPersistent(b.N, 10000)
+}
+
+// benchmarkPersistentUnion checks the performance of the persistentset.Union() method on two closely
+// related versions of a set
+func benchmarkPersistentUnion(n int, size int) {
+	s := NewPersistent(persistentBenchValues(size)...)
+	t := s.With(-1).Without(0)
+
+	// Run persistentset.Union() n times
+	for i := 0; i < n; i++ {
+		s.Union(t)
+	}
+}
+
+// BenchmarkPersistentUnionSmall checks the performance of the persistentset.Union() method over a
+// small data set
+func BenchmarkPersistentUnionSmall(b *testing.B) {
+	benchmarkPersistentUnion(b.N, 10)
+}
+
+// BenchmarkPersistentUnionLarge checks the performance of the persistentset.Union() method over a
+// large data set
+func BenchmarkPersistentUnionLarge(b *testing.B) {
+	benchmarkPersistentUnion(b.N, 10000)
+}
@@ -0,0 +1,180 @@
+package set
+
+import (
+	"log"
+	"math/rand"
+	"testing"
+)
+
+// TestPersistentWithWithout verifies that the persistentset.With() and persistentset.Without() methods
+// create new versions without modifying the original
+func TestPersistentWithWithout(t *testing.T) {
+	log.Println("TestPersistentWithWithout()")
+
+	var empty PersistentSet
+	if empty.Size() != 0 || empty.Has(1) || empty.Without(1) != &empty {
+		t.Fatalf("set.PersistentSet - zero value is not an empty set")
+	}
+
+	// Build successive versions, keeping every one
+	versions := []*PersistentSet{&empty}
+	for i := 0; i < 1000; i++ {
+		versions = append(versions, versions[len(versions)-1].With(i))
+	}
+
+	// Every version contains exactly the elements added before it
+	for i, v := range versions {
+		if v.Size() != i || (i > 0 && !v.Has(i-1)) || v.Has(i) {
+			t.Fatalf("set.PersistentSet.With() - unexpected version %d: size %d", i, v.Size())
+		}
+	}
+
+	// Adding an existing element, or removing a missing element, returns the same set
+	last := versions[len(versions)-1]
+	if last.With(500) != last || last.Without(-1) != last {
+		t.Fatalf("set.PersistentSet - unchanged set not reused")
+	}
+
+	// Remove every element, checking that previous versions are unchanged
+	s := last
+	for i := 0; i < 1000; i++ {
+		s = s.Without(i)
+		if s.Size() != 999-i || s.Has(i) {
+			t.Fatalf("set.PersistentSet.Without() - unexpected result after removing %d: size %d", i, s.Size())
+		}
+	}
+	if s.root != nil || last.Size() != 1000 || !last.Has(0) {
+		t.Fatalf("set.PersistentSet.Without() - original set modified")
+	}
+}
+
+// TestPersistentAlgebra verifies that persistent set algebra matches Set algebra
+func TestPersistentAlgebra(t *testing.T) {
+	log.Println("TestPersistentAlgebra()")
+
+	r := rand.New(rand.NewSource(1))
+	random := func(n int) *Set {
+		s := New()
+		for i := 0; i < n; i++ {
+			s.Add(r.Intn(200))
+		}
+		return s
+	}
+
+	for i := 0; i < 200; i++ {
+		x, y := random(r.Intn(100)), random(r.Intn(100))
+		if i%4 == 0 {
+			// Closely related sets share structure
+			y = x.Clone()
+			y.Add(-1)
+			y.RemoveAll(x.Enumerate()[0:min(1, x.Size())]...)
+		}
+
+		px := PersistentSetFrom(x)
+		py := PersistentSetFrom(y)
+		if i%2 == 0 {
+			// Derive the second set from the first, so that they share structure
+			py = px
+			for v := range px.All() {
+				if !y.Has(v) {
+					py = py.Without(v)
+				}
+			}
+			for v := range y.All() {
+				py = py.With(v)
+			}
+		}
+
+		// Create a table of operations and their expected results
+		var tests = []struct {
+			name   string
+			result *PersistentSet
+			target *Set
+		}{
+			{"Union", px.Union(py), x.Union(y)},
+			{"Intersection", px.Intersection(py), x.Intersection(y)},
+			{"Difference", px.Difference(py), x.Difference(y)},
+			{"SymmetricDifference", px.SymmetricDifference(py), x.SymmetricDifference(y)},
+		}
+
+		// Iterate test table, checking results
+		for _, test := range tests {
+			if test.result.Size() != test.target.Size() || !test.result.ToSet().Equal(test.target) {
+				t.Fatalf("set.PersistentSet.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+			}
+
+			// Results built by algebra are identical to those built directly
+			if !test.result.Equal(PersistentSetFrom(test.target)) {
+				t.Fatalf("set.PersistentSet.%s() - result not equal to direct construction", test.name)
+			}
+		}
+
+		if px.Subset(py) != x.Subset(y) || px.Equal(py) != x.Equal(y) {
+			t.Fatalf("set.PersistentSet.Subset() - unexpected result for %s and %s", x, y)
+		}
+	}
+}
+
+// TestPersistentSharing verifies that set algebra reuses shared structure
+func TestPersistentSharing(t *testing.T) {
+	log.Println("TestPersistentSharing()")
+
+	values := make([]interface{}, 1000)
+	for i := range values {
+		values[i] = i
+	}
+	s := NewPersistent(values...)
+	u := s.With(1000)
+
+	// Only the path to the new element is copied
+	shared := 0
+	for i, e := range u.root.entries {
+		if j, ok := s.root.at(uint32(1) << uint(i)); ok && s.root.entries[j].node == e.node {
+			shared++
+		}
+	}
+	if shared < len(u.root.entries)-1 {
+		t.Fatalf("set.PersistentSet.With() - only %d of %d children shared", shared, len(u.root.entries))
+	}
+
+	// Algebra on related versions returns existing versions where possible
+	if s.Union(u).root != u.root || u.Intersection(s).root != s.root || s.Difference(u).Size() != 0 {
+		t.Fatalf("set.PersistentSet - algebra did not reuse existing versions")
+	}
+	if !s.Union(s).Equal(s) || s.Intersection(s).root != s.root || !u.Subset(s) || s.Subset(u) {
+		t.Fatalf("set.PersistentSet - unexpected algebra on identical sets")
+	}
+}
+
+// TestPersistentCollisions verifies that elements whose hashes are identical are stored correctly
+func TestPersistentCollisions(t *testing.T) {
+	log.Println("TestPersistentCollisions()")
+
+	// Construct leaves with identical hashes directly, as real collisions cannot be produced on demand
+	a := hamtEntry{hash: 42, value: "a"}
+	b := hamtEntry{hash: 42, value: "b"}
+	c := hamtEntry{hash: 42, value: "c"}
+	d := hamtEntry{hash: 43, value: "d"}
+
+	x := &PersistentSet{root: hamtRoot(hamtBuild([]hamtEntry{a, b, d, a}, 0))}
+	if x.Size() != 3 || !x.root.has(a, 0) || !x.root.has(b, 0) || x.root.has(c, 0) {
+		t.Fatalf("set.PersistentSet - unexpected collision result: %s", x)
+	}
+
+	root, added := x.root.with(c, 0)
+	y := &PersistentSet{root: root}
+	if !added || y.Size() != 4 || !y.root.has(c, 0) || x.root.has(c, 0) {
+		t.Fatalf("set.PersistentSet - collision not added: %s", y)
+	}
+
+	e, ok, removed := y.root.without(a, 0)
+	z := &PersistentSet{root: hamtRoot(e, ok)}
+	if !removed || z.Size() != 3 || z.root.has(a, 0) || !z.root.has(b, 0) {
+		t.Fatalf("set.PersistentSet - collision not removed: %s", z)
+	}
+
+	// Algebra on collision nodes
+	if !x.Union(z).Equal(y) || x.Intersection(z).Size() != 2 || x.Difference(z).Size() != 1 || !y.Subset(z) || z.Subset(x) {
+		t.Fatalf("set.PersistentSet - unexpected algebra on collisions")
+	}
+}
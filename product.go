@@ -0,0 +1,120 @@
+package set
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+)
+
+// CartesianPairs returns an iterator over ordered pairs of every permutation between two sets, for use
+// with a range loop.  Unlike CartesianProduct, pairs are generated on demand, so memory use is
+// proportional to the size of the two sets, rather than the size of their product.
+//
+// The elements of both sets are gathered when iteration begins, so the sets may be modified while
+// iteration is in progress without affecting the pairs produced.
+func (s *Set) CartesianPairs(t *Set) iter.Seq[Pair] {
+	return cartesianPairsOf(s.Enumerate, t.Enumerate)
+}
+
+// cartesianPairsOf returns an iterator over ordered pairs of the elements returned by two functions,
+// which are called when iteration begins
+func cartesianPairsOf(xsOf func() []interface{}, ysOf func() []interface{}) iter.Seq[Pair] {
+	return func(yield func(Pair) bool) {
+		// Gather the elements of both sets
+		xs := xsOf()
+		ys := ysOf()
+
+		// Enumerate the source set
+		for _, x := range xs {
+			// Enumerate the target set
+			for _, y := range ys {
+				// Create pair, stopping early if requested
+				if !yield(Pair{X: x, Y: y}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Product returns an iterator over the n-ary cartesian product of any number of sets, for use with a
+// range loop.  Each tuple holds one element from each set, in the same order as the sets were specified.
+// Tuples are generated on demand, so memory use is proportional to the size of the sets, rather than the
+// size of their product.
+//
+// To avoid an allocation per tuple, the same slice is reused for every tuple produced.  Callers which
+// retain a tuple after the loop body must copy it.
+//
+// The product of zero sets is a single, empty tuple, and the product of any empty set is empty.  The
+// elements of all sets are gathered when iteration begins.
+func Product(sets ...*Set) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		// Gather the elements of all sets, stopping if any set is empty
+		elements := make([][]interface{}, len(sets))
+		for i, s := range sets {
+			elements[i] = s.Enumerate()
+			if len(elements[i]) == 0 {
+				return
+			}
+		}
+
+		// Track the position within each set, and fill the first tuple
+		indices := make([]int, len(sets))
+		tuple := make([]interface{}, len(sets))
+		for i := range elements {
+			tuple[i] = elements[i][0]
+		}
+
+		for {
+			if !yield(tuple) {
+				return
+			}
+
+			// Advance positions like an odometer, with the last set changing fastest
+			i := len(sets) - 1
+			for ; i >= 0; i-- {
+				indices[i]++
+				if indices[i] < len(elements[i]) {
+					tuple[i] = elements[i][indices[i]]
+					break
+				}
+
+				// Wrap this position, and carry into the previous set
+				indices[i] = 0
+				tuple[i] = elements[i][0]
+			}
+
+			// All positions have wrapped, so every tuple has been produced
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
+
+// ProductSize returns the number of tuples in the n-ary cartesian product of any number of sets,
+// without generating any tuples.  If the size does not fit in an int, ProductSize returns false.
+func ProductSize(sets ...*Set) (int, bool) {
+	// Gather the size of all sets, since the product of any empty set is empty
+	sizes := make([]int, len(sets))
+	for i, s := range sets {
+		sizes[i] = s.Size()
+		if sizes[i] == 0 {
+			return 0, true
+		}
+	}
+
+	// The product of zero sets is a single, empty tuple
+	size := uint64(1)
+	for _, n := range sizes {
+		// Multiply sizes, checking for overflow
+		hi, lo := bits.Mul64(size, uint64(n))
+		if hi != 0 || lo > math.MaxInt {
+			return 0, false
+		}
+
+		size = lo
+	}
+
+	return int(size), true
+}
@@ -0,0 +1,123 @@
+package set
+
+import (
+	"fmt"
+	"log"
+	"testing"
+)
+
+// TestCartesianPairs verifies that the set.CartesianPairs() method is working properly
+func TestCartesianPairs(t *testing.T) {
+	log.Println("TestCartesianPairs()")
+
+	// Create a set, add some initial values
+	set := New(1, 2)
+
+	// Create a table of tests and expected results of Set cartesian products
+	var tests = []*Set{
+		New(1, 2),
+		New(3, 4, 5),
+		New(),
+	}
+
+	// Iterate test table, checking results against the materialized product
+	for _, test := range tests {
+		product := New()
+		for p := range set.CartesianPairs(test) {
+			product.Add(p)
+		}
+
+		if target := set.CartesianProduct(test); !product.Equal(target) {
+			t.Fatalf("set.CartesianPairs() - sets not equal: %s != %s", product, target)
+		}
+
+		log.Println(set, "×", test, "=", product)
+	}
+
+	// Stop early
+	count := 0
+	for range set.CartesianPairs(New(3, 4, 5)) {
+		count++
+		if count == 4 {
+			break
+		}
+	}
+	if count != 4 {
+		t.Fatalf("set.CartesianPairs() - unexpected number of pairs after break: %d", count)
+	}
+}
+
+// TestProduct verifies that the Product() and ProductSize() functions are working properly
+func TestProduct(t *testing.T) {
+	log.Println("TestProduct()")
+
+	// Create a table of tests and expected sizes of n-ary products
+	var tests = []struct {
+		sets []*Set
+		size int
+	}{
+		// No sets
+		{nil, 1},
+		// One set
+		{[]*Set{New(1, 2, 3)}, 3},
+		// Three sets
+		{[]*Set{New(1, 2), New("a", "b", "c"), New(true, false)}, 12},
+		// Empty set
+		{[]*Set{New(1, 2), New(), New(3)}, 0},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		// Verify the size is computed up front
+		size, ok := ProductSize(test.sets...)
+		if !ok || size != test.size {
+			t.Fatalf("ProductSize() - unexpected result: %d, %t", size, ok)
+		}
+
+		// Verify every tuple is distinct, has one element from each set, and the count matches
+		seen := New()
+		for tuple := range Product(test.sets...) {
+			if len(tuple) != len(test.sets) {
+				t.Fatalf("Product() - unexpected tuple length: %d", len(tuple))
+			}
+
+			for i, e := range tuple {
+				if !test.sets[i].Has(e) {
+					t.Fatalf("Product() - element %v not in set %d", e, i)
+				}
+			}
+
+			seen.Add(fmt.Sprint(tuple))
+		}
+		if seen.Size() != test.size {
+			t.Fatalf("Product() - unexpected number of tuples: %d", seen.Size())
+		}
+
+		log.Println("×", test.sets, "=", seen.Size())
+	}
+}
+
+// TestProductSizeOverflow verifies that the ProductSize() function detects overflow
+func TestProductSizeOverflow(t *testing.T) {
+	log.Println("TestProductSizeOverflow()")
+
+	// 16 sets of 256 elements have a product of 2^128 tuples
+	large := New()
+	for i := 0; i < 256; i++ {
+		large.Add(i)
+	}
+
+	sets := make([]*Set, 16)
+	for i := range sets {
+		sets[i] = large
+	}
+
+	if _, ok := ProductSize(sets...); ok {
+		t.Fatalf("ProductSize() - expected overflow")
+	}
+
+	// Adding an empty set makes the product empty, regardless of overflow
+	if size, ok := ProductSize(append(sets, New())...); !ok || size != 0 {
+		t.Fatalf("ProductSize() - unexpected result with empty set: %d, %t", size, ok)
+	}
+}
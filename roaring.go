@@ -0,0 +1,1003 @@
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+)
+
+const (
+	// roaringArrayMax is the largest number of elements held by an array container
+	roaringArrayMax = 4096
+	// roaringBitmapWords is the number of words in a bitmap container
+	roaringBitmapWords = 1024
+
+	// Cookies which identify the portable Roaring serialization format
+	roaringCookieNoRuns = 12346
+	roaringCookie       = 12347
+	// Below this many containers, serialized sets with run containers omit the offset header
+	roaringNoOffsetThreshold = 4
+)
+
+// Kinds of containers which may hold the low 16 bits of elements in a RoaringSet
+const (
+	roaringArray = iota
+	roaringBitmap
+	roaringRun
+)
+
+// errRoaringCorrupt is returned when a serialized RoaringSet cannot be decoded
+var errRoaringCorrupt = errors.New("set: corrupt roaring set")
+
+// RoaringSet represents an unordered collection of unique 32-bit unsigned integers, compressed using
+// Roaring bitmaps.  Elements are partitioned by their high 16 bits into containers, each of which
+// holds the low 16 bits of its elements as a sorted array, a bitmap or a list of runs, depending on
+// which is most compact.  RoaringSet is well suited to large, sparse sets of integer IDs.
+//
+// RoaringSet implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler using the portable
+// Roaring serialization format, which is shared by Roaring implementations in other languages.
+type RoaringSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// High 16 bits of the elements in each container, in ascending order
+	keys []uint16
+	// Containers holding the low 16 bits of elements, in the same order as keys
+	containers []*roaringContainer
+}
+
+// roaringContainer holds the low 16 bits of all elements in a RoaringSet which share the same high
+// 16 bits.  Array containers hold at most roaringArrayMax elements, and bitmap containers hold more.
+type roaringContainer struct {
+	// Kind of container, which determines which of the fields below are used
+	kind int
+	// Number of elements in the container
+	card int
+	// Sorted elements, for array containers
+	array []uint16
+	// Bits representing membership of each element, for bitmap containers
+	bitmap []uint64
+	// Sorted, non-overlapping runs of elements, for run containers
+	runs []roaringRunPair
+}
+
+// roaringOp describes a set operation by which elements it keeps, according to whether they are present
+// in only the current set, only the parameter set, or both sets
+type roaringOp struct {
+	onlyS bool
+	onlyT bool
+	both  bool
+}
+
+// roaringRunPair is a run of consecutive elements in a run container, beginning at start and
+// containing length+1 elements
+type roaringRunPair struct {
+	start  uint16
+	length uint16
+}
+
+// NewRoaring creates a new RoaringSet, optionally adding initial elements to the set
+func NewRoaring(values ...uint32) *RoaringSet {
+	// Initialize set
+	s := RoaringSet{}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// RoaringSetFrom converts a Set of integer elements into a RoaringSet, returning an error if any
+// element of the set is not a uint32, or an int in the range of a uint32
+func RoaringSetFrom(t *Set) (*RoaringSet, error) {
+	// Create the output set
+	s := NewRoaring()
+
+	// Enumerate the source set, checking each element
+	for _, e := range t.Enumerate() {
+		switch v := e.(type) {
+		case uint32:
+			s.Add(v)
+		case int:
+			if v < 0 || uint64(v) > math.MaxUint32 {
+				return nil, fmt.Errorf("set: element %d is out of range for a roaring set", v)
+			}
+
+			s.Add(uint32(v))
+		default:
+			return nil, fmt.Errorf("set: element %v of type %T is not an integer", e, e)
+		}
+	}
+
+	return s, nil
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed
+func (s *RoaringSet) Add(value uint32) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Find the container for the element, creating it if needed
+	hi, lo := uint16(value>>16), uint16(value)
+	i, ok := s.find(hi)
+	if !ok {
+		s.keys = append(s.keys, 0)
+		copy(s.keys[i+1:], s.keys[i:])
+		s.keys[i] = hi
+
+		s.containers = append(s.containers, nil)
+		copy(s.containers[i+1:], s.containers[i:])
+		s.containers[i] = &roaringContainer{}
+	}
+
+	return s.containers[i].add(lo)
+}
+
+// Clone copies the current set into a new, identical set
+func (s *RoaringSet) Clone() *RoaringSet {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Copy keys and containers into a new set
+	outSet := &RoaringSet{
+		keys:       make([]uint16, len(s.keys)),
+		containers: make([]*roaringContainer, len(s.containers)),
+	}
+	copy(outSet.keys, s.keys)
+	for i, c := range s.containers {
+		outSet.containers[i] = c.clone()
+	}
+
+	return outSet
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *RoaringSet) Difference(t *RoaringSet) *RoaringSet {
+	return s.combine(t, roaringOp{onlyS: true})
+}
+
+// Enumerate returns a slice of all elements in the set, in ascending order
+func (s *RoaringSet) Enumerate() []uint32 {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice, combining the key and low bits of each element
+	values := make([]uint32, 0, s.size())
+	for i, c := range s.containers {
+		hi := uint32(s.keys[i]) << 16
+		c.each(func(lo uint16) {
+			values = append(values, hi|uint32(lo))
+		})
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets contain exactly the same elements
+func (s *RoaringSet) Equal(t *RoaringSet) bool {
+	return s.Size() == t.Size() && t.Difference(s).Size() == 0
+}
+
+// Has checks for membership of an element in the set
+func (s *RoaringSet) Has(value uint32) bool {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Find the container for the element, and check for membership within it
+	i, ok := s.find(uint16(value >> 16))
+	return ok && s.containers[i].has(uint16(value))
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *RoaringSet) Intersection(t *RoaringSet) *RoaringSet {
+	return s.combine(t, roaringOp{both: true})
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the set using the portable Roaring
+// serialization format
+func (s *RoaringSet) MarshalBinary() ([]byte, error) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check for run containers, which use a different header
+	n := len(s.containers)
+	hasRuns := false
+	for _, c := range s.containers {
+		if c.kind == roaringRun {
+			hasRuns = true
+			break
+		}
+	}
+
+	// Write the cookie, and a bitset of run containers if needed
+	var b []byte
+	if hasRuns {
+		b = binary.LittleEndian.AppendUint32(b, uint32(roaringCookie)|uint32(n-1)<<16)
+
+		runBits := make([]byte, (n+7)/8)
+		for i, c := range s.containers {
+			if c.kind == roaringRun {
+				runBits[i/8] |= 1 << uint(i%8)
+			}
+		}
+		b = append(b, runBits...)
+	} else {
+		b = binary.LittleEndian.AppendUint32(b, roaringCookieNoRuns)
+		b = binary.LittleEndian.AppendUint32(b, uint32(n))
+	}
+
+	// Write the key and cardinality of each container
+	for i, c := range s.containers {
+		b = binary.LittleEndian.AppendUint16(b, s.keys[i])
+		b = binary.LittleEndian.AppendUint16(b, uint16(c.card-1))
+	}
+
+	// Write the offset of each container, if needed
+	if !hasRuns || n >= roaringNoOffsetThreshold {
+		offset := len(b) + 4*n
+		for _, c := range s.containers {
+			b = binary.LittleEndian.AppendUint32(b, uint32(offset))
+			offset += c.serializedSize()
+		}
+	}
+
+	// Write each container
+	for _, c := range s.containers {
+		switch c.kind {
+		case roaringArray:
+			for _, v := range c.array {
+				b = binary.LittleEndian.AppendUint16(b, v)
+			}
+		case roaringBitmap:
+			for _, w := range c.bitmap {
+				b = binary.LittleEndian.AppendUint64(b, w)
+			}
+		case roaringRun:
+			b = binary.LittleEndian.AppendUint16(b, uint16(len(c.runs)))
+			for _, r := range c.runs {
+				b = binary.LittleEndian.AppendUint16(b, r.start)
+				b = binary.LittleEndian.AppendUint16(b, r.length)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// Rank returns the number of elements in the set which are less than the parameter value.  If the
+// value is a member of the set, this is its zero-based position in ascending order.
+func (s *RoaringSet) Rank(value uint32) int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Count all elements in containers before the value's container, and those before the value
+	// within its container
+	hi, lo := uint16(value>>16), uint16(value)
+	rank := 0
+	for i, c := range s.containers {
+		if s.keys[i] > hi {
+			break
+		}
+		if s.keys[i] == hi {
+			rank += c.rank(lo)
+			break
+		}
+
+		rank += c.card
+	}
+
+	return rank
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *RoaringSet) Remove(value uint32) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Find the container for the element
+	i, ok := s.find(uint16(value >> 16))
+	if !ok || !s.containers[i].remove(uint16(value)) {
+		return false
+	}
+
+	// Remove the container once it is empty
+	if s.containers[i].card == 0 {
+		s.keys = append(s.keys[:i], s.keys[i+1:]...)
+		s.containers = append(s.containers[:i], s.containers[i+1:]...)
+	}
+
+	return true
+}
+
+// RunOptimize converts containers to run containers wherever doing so makes them more compact, which
+// is worthwhile for sets containing long runs of consecutive elements.  Run containers are converted
+// back as needed when elements are added or removed.
+func (s *RoaringSet) RunOptimize() {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, c := range s.containers {
+		c.runOptimize()
+	}
+}
+
+// Select returns the element at the zero-based position i in ascending order, and whether or not
+// such an element exists
+func (s *RoaringSet) Select(i int) (uint32, bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check bounds
+	if i < 0 {
+		return 0, false
+	}
+
+	// Skip entire containers until reaching the one which holds the position
+	for j, c := range s.containers {
+		if i < c.card {
+			return uint32(s.keys[j])<<16 | uint32(c.selectAt(i)), true
+		}
+
+		i -= c.card
+	}
+
+	return 0, false
+}
+
+// Size returns the size or cardinality of this set
+func (s *RoaringSet) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.size()
+}
+
+// String returns a string representation of this set, with elements in ascending order
+func (s *RoaringSet) String() string {
+	// Print identifier
+	str := "{ "
+
+	// Check for empty set, print symbol if empty
+	values := s.Enumerate()
+	if len(values) == 0 {
+		return str + "Ø }"
+	}
+
+	// Print all elements
+	for _, v := range values {
+		str = str + fmt.Sprintf("%d ", v)
+	}
+
+	return str + "}"
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *RoaringSet) Subset(t *RoaringSet) bool {
+	return t.Difference(s).Size() == 0
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *RoaringSet) SymmetricDifference(t *RoaringSet) *RoaringSet {
+	return s.combine(t, roaringOp{onlyS: true, onlyT: true})
+}
+
+// ToSet copies the elements of the current set into a new Set of uint32 elements
+func (s *RoaringSet) ToSet() *Set {
+	// Copy set into a new set
+	outSet := New()
+	for _, v := range s.Enumerate() {
+		outSet.Add(v)
+	}
+
+	return outSet
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *RoaringSet) Union(t *RoaringSet) *RoaringSet {
+	return s.combine(t, roaringOp{onlyS: true, onlyT: true, both: true})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding a set which was encoded using the
+// portable Roaring serialization format, and replacing the contents of the current set
+func (s *RoaringSet) UnmarshalBinary(b []byte) error {
+	r := roaringReader{b: b}
+
+	// Read the cookie, which determines the number of containers and whether any are run containers
+	cookie := r.uint32()
+	var n int
+	var runBits []byte
+	switch {
+	case cookie == roaringCookieNoRuns:
+		n = int(r.uint32())
+	case cookie&0xffff == roaringCookie:
+		n = int(cookie>>16) + 1
+		runBits = r.bytes((n + 7) / 8)
+	default:
+		return fmt.Errorf("set: unknown roaring set cookie: %d", cookie)
+	}
+	if r.err != nil || n > 1<<16 {
+		return errRoaringCorrupt
+	}
+
+	// Read the key and cardinality of each container
+	keys := make([]uint16, n)
+	cards := make([]int, n)
+	for i := 0; i < n; i++ {
+		keys[i] = r.uint16()
+		cards[i] = int(r.uint16()) + 1
+
+		// Keys must be strictly ascending
+		if i > 0 && keys[i] <= keys[i-1] {
+			return errRoaringCorrupt
+		}
+	}
+
+	// Skip the offset of each container, since containers are read in order
+	if runBits == nil || n >= roaringNoOffsetThreshold {
+		r.bytes(4 * n)
+	}
+
+	// Read each container
+	containers := make([]*roaringContainer, n)
+	for i := 0; i < n; i++ {
+		c := &roaringContainer{
+			card: cards[i],
+		}
+
+		switch {
+		case runBits != nil && runBits[i/8]&(1<<uint(i%8)) != 0:
+			c.kind = roaringRun
+			c.runs = make([]roaringRunPair, r.uint16())
+			for j := range c.runs {
+				c.runs[j] = roaringRunPair{start: r.uint16(), length: r.uint16()}
+			}
+		case c.card > roaringArrayMax:
+			c.kind = roaringBitmap
+			c.bitmap = make([]uint64, roaringBitmapWords)
+			for j := range c.bitmap {
+				c.bitmap[j] = r.uint64()
+			}
+		default:
+			c.kind = roaringArray
+			c.array = make([]uint16, c.card)
+			for j := range c.array {
+				c.array[j] = r.uint16()
+			}
+		}
+
+		if r.err != nil || !c.valid() {
+			return errRoaringCorrupt
+		}
+
+		containers[i] = c
+	}
+
+	// Lock set for write, and replace its contents
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys = keys
+	s.containers = containers
+	return nil
+}
+
+// combine creates a new set by applying an operation to the containers of the current set and the
+// parameter set.  Containers which are present in only one set are copied into the new set if the
+// operation keeps elements present only in that set, and containers present in both sets are combined.
+func (s *RoaringSet) combine(t *RoaringSet, op roaringOp) *RoaringSet {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Merge the ordered keys of both sets
+	outSet := NewRoaring()
+	add := func(key uint16, c *roaringContainer) {
+		if c != nil && c.card > 0 {
+			outSet.keys = append(outSet.keys, key)
+			outSet.containers = append(outSet.containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(s.keys) || j < len(t.keys) {
+		switch {
+		case j == len(t.keys) || (i < len(s.keys) && s.keys[i] < t.keys[j]):
+			if op.onlyS {
+				add(s.keys[i], s.containers[i].clone())
+			}
+			i++
+		case i == len(s.keys) || t.keys[j] < s.keys[i]:
+			if op.onlyT {
+				add(t.keys[j], t.containers[j].clone())
+			}
+			j++
+		default:
+			add(s.keys[i], combineContainers(s.containers[i], t.containers[j], op))
+			i++
+			j++
+		}
+	}
+
+	return outSet
+}
+
+// find returns the index of the container with the specified key, or the index at which such a
+// container would be inserted, and whether or not the container exists.  The caller must hold the lock.
+func (s *RoaringSet) find(key uint16) (int, bool) {
+	i := sort.Search(len(s.keys), func(i int) bool {
+		return s.keys[i] >= key
+	})
+
+	return i, i < len(s.keys) && s.keys[i] == key
+}
+
+// size returns the number of elements in all containers.  The caller must hold the lock.
+func (s *RoaringSet) size() int {
+	size := 0
+	for _, c := range s.containers {
+		size += c.card
+	}
+
+	return size
+}
+
+// combineContainers creates a new container by applying an operation to two containers.  Sparse
+// containers are combined without expanding them into bitmaps: two arrays are merged, and an array is
+// filtered by membership of the other container when the result can only hold its elements.
+func combineContainers(a *roaringContainer, b *roaringContainer, op roaringOp) *roaringContainer {
+	switch {
+	case a.kind == roaringArray && b.kind == roaringArray:
+		return containerFromArray(mergeArrays(a.array, b.array, op))
+	case a.kind == roaringArray && !op.onlyT:
+		return containerFromArray(filterArray(a.array, b, op.both, op.onlyS))
+	case b.kind == roaringArray && !op.onlyS:
+		return containerFromArray(filterArray(b.array, a, op.both, op.onlyT))
+	}
+
+	// Combine dense containers a word at a time
+	x, y := a.words(), b.words()
+	for i := range x {
+		var w uint64
+		if op.onlyS {
+			w |= x[i] &^ y[i]
+		}
+		if op.onlyT {
+			w |= y[i] &^ x[i]
+		}
+		if op.both {
+			w |= x[i] & y[i]
+		}
+		x[i] = w
+	}
+
+	return containerFromWords(x)
+}
+
+// containerFromArray creates a container from a sorted array, choosing a bitmap container if it holds
+// too many elements
+func containerFromArray(array []uint16) *roaringContainer {
+	c := &roaringContainer{
+		kind:  roaringArray,
+		card:  len(array),
+		array: array,
+	}
+
+	if c.card > roaringArrayMax {
+		c.toBitmap()
+	}
+
+	return c
+}
+
+// containerFromWords creates a container from a bitmap, choosing an array container if it holds few
+// enough elements
+func containerFromWords(words []uint64) *roaringContainer {
+	c := &roaringContainer{
+		kind:   roaringBitmap,
+		bitmap: words,
+	}
+	for _, w := range words {
+		c.card += bits.OnesCount64(w)
+	}
+
+	if c.card <= roaringArrayMax {
+		c.toArray()
+	}
+
+	return c
+}
+
+// filterArray returns the values of a sorted array which are kept according to whether or not they are
+// present in a container
+func filterArray(array []uint16, c *roaringContainer, keepIn bool, keepOut bool) []uint16 {
+	out := make([]uint16, 0, len(array))
+	for _, v := range array {
+		if c.has(v) {
+			if keepIn {
+				out = append(out, v)
+			}
+		} else if keepOut {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// mergeArrays merges two sorted arrays, keeping the values selected by an operation, in sorted order
+func mergeArrays(x []uint16, y []uint16, op roaringOp) []uint16 {
+	out := make([]uint16, 0, len(x)+len(y))
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case j == len(y) || (i < len(x) && x[i] < y[j]):
+			if op.onlyS {
+				out = append(out, x[i])
+			}
+			i++
+		case i == len(x) || y[j] < x[i]:
+			if op.onlyT {
+				out = append(out, y[j])
+			}
+			j++
+		default:
+			if op.both {
+				out = append(out, x[i])
+			}
+			i++
+			j++
+		}
+	}
+
+	return out
+}
+
+// add inserts a value into the container, returning true if it was newly added
+func (c *roaringContainer) add(value uint16) bool {
+	c.unrun()
+
+	switch c.kind {
+	case roaringArray:
+		// Find the insertion point, checking for existence
+		i := sort.Search(len(c.array), func(i int) bool {
+			return c.array[i] >= value
+		})
+		if i < len(c.array) && c.array[i] == value {
+			return false
+		}
+
+		// Full array containers become bitmap containers
+		if len(c.array) == roaringArrayMax {
+			c.toBitmap()
+			return c.add(value)
+		}
+
+		c.array = append(c.array, 0)
+		copy(c.array[i+1:], c.array[i:])
+		c.array[i] = value
+	case roaringBitmap:
+		w, b := value/64, uint64(1)<<(value%64)
+		if c.bitmap[w]&b != 0 {
+			return false
+		}
+
+		c.bitmap[w] |= b
+	}
+
+	c.card++
+	return true
+}
+
+// clone copies the container into a new, identical container
+func (c *roaringContainer) clone() *roaringContainer {
+	return &roaringContainer{
+		kind:   c.kind,
+		card:   c.card,
+		array:  append([]uint16(nil), c.array...),
+		bitmap: append([]uint64(nil), c.bitmap...),
+		runs:   append([]roaringRunPair(nil), c.runs...),
+	}
+}
+
+// each calls a function for each value in the container, in ascending order
+func (c *roaringContainer) each(fn func(uint16)) {
+	switch c.kind {
+	case roaringArray:
+		for _, v := range c.array {
+			fn(v)
+		}
+	case roaringBitmap:
+		for i, w := range c.bitmap {
+			for w != 0 {
+				fn(uint16(i*64 + bits.TrailingZeros64(w)))
+				w &= w - 1
+			}
+		}
+	case roaringRun:
+		for _, r := range c.runs {
+			for v := int(r.start); v <= int(r.start)+int(r.length); v++ {
+				fn(uint16(v))
+			}
+		}
+	}
+}
+
+// has checks for membership of a value in the container
+func (c *roaringContainer) has(value uint16) bool {
+	switch c.kind {
+	case roaringArray:
+		i := sort.Search(len(c.array), func(i int) bool {
+			return c.array[i] >= value
+		})
+		return i < len(c.array) && c.array[i] == value
+	case roaringBitmap:
+		return c.bitmap[value/64]&(uint64(1)<<(value%64)) != 0
+	case roaringRun:
+		// Find the last run which begins at or before the value
+		i := sort.Search(len(c.runs), func(i int) bool {
+			return c.runs[i].start > value
+		}) - 1
+		return i >= 0 && int(value) <= int(c.runs[i].start)+int(c.runs[i].length)
+	}
+
+	return false
+}
+
+// rank returns the number of values in the container which are less than the parameter value
+func (c *roaringContainer) rank(value uint16) int {
+	switch c.kind {
+	case roaringArray:
+		return sort.Search(len(c.array), func(i int) bool {
+			return c.array[i] >= value
+		})
+	case roaringBitmap:
+		rank := 0
+		for _, w := range c.bitmap[:value/64] {
+			rank += bits.OnesCount64(w)
+		}
+		return rank + bits.OnesCount64(c.bitmap[value/64]&(uint64(1)<<(value%64)-1))
+	case roaringRun:
+		rank := 0
+		for _, r := range c.runs {
+			if r.start >= value {
+				break
+			}
+
+			rank += min(int(r.length)+1, int(value)-int(r.start))
+		}
+		return rank
+	}
+
+	return 0
+}
+
+// remove destroys a value in the container, returning true if it existed
+func (c *roaringContainer) remove(value uint16) bool {
+	c.unrun()
+
+	switch c.kind {
+	case roaringArray:
+		// Find the value, checking for existence
+		i := sort.Search(len(c.array), func(i int) bool {
+			return c.array[i] >= value
+		})
+		if i == len(c.array) || c.array[i] != value {
+			return false
+		}
+
+		c.array = append(c.array[:i], c.array[i+1:]...)
+		c.card--
+	case roaringBitmap:
+		w, b := value/64, uint64(1)<<(value%64)
+		if c.bitmap[w]&b == 0 {
+			return false
+		}
+
+		c.bitmap[w] &^= b
+		c.card--
+
+		// Sparse bitmap containers become array containers
+		if c.card <= roaringArrayMax {
+			c.toArray()
+		}
+	}
+
+	return true
+}
+
+// runOptimize converts the container to a run container, if doing so makes it more compact
+func (c *roaringContainer) runOptimize() {
+	if c.kind == roaringRun {
+		return
+	}
+
+	// Gather the runs of consecutive values in the container
+	var runs []roaringRunPair
+	c.each(func(v uint16) {
+		if n := len(runs); n > 0 && int(runs[n-1].start)+int(runs[n-1].length)+1 == int(v) {
+			runs[n-1].length++
+			return
+		}
+
+		runs = append(runs, roaringRunPair{start: v})
+	})
+
+	// Convert the container only if runs are smaller than the current representation
+	run := &roaringContainer{kind: roaringRun, runs: runs}
+	if run.serializedSize() < c.serializedSize() {
+		c.kind, c.array, c.bitmap, c.runs = roaringRun, nil, nil, runs
+	}
+}
+
+// selectAt returns the value at the zero-based position i in ascending order, which must be less
+// than the cardinality of the container
+func (c *roaringContainer) selectAt(i int) uint16 {
+	switch c.kind {
+	case roaringArray:
+		return c.array[i]
+	case roaringBitmap:
+		for j, w := range c.bitmap {
+			if n := bits.OnesCount64(w); i >= n {
+				i -= n
+				continue
+			}
+
+			// Clear lower set bits until reaching the position
+			for ; i > 0; i-- {
+				w &= w - 1
+			}
+			return uint16(j*64 + bits.TrailingZeros64(w))
+		}
+	case roaringRun:
+		for _, r := range c.runs {
+			if n := int(r.length) + 1; i >= n {
+				i -= n
+				continue
+			}
+
+			return r.start + uint16(i)
+		}
+	}
+
+	return 0
+}
+
+// serializedSize returns the number of bytes used by the container in the portable serialization format
+func (c *roaringContainer) serializedSize() int {
+	switch c.kind {
+	case roaringArray:
+		return 2 * len(c.array)
+	case roaringBitmap:
+		return 8 * roaringBitmapWords
+	default:
+		return 2 + 4*len(c.runs)
+	}
+}
+
+// toArray converts a bitmap container to an array container
+func (c *roaringContainer) toArray() {
+	array := make([]uint16, 0, c.card)
+	c.each(func(v uint16) {
+		array = append(array, v)
+	})
+
+	c.kind, c.array, c.bitmap = roaringArray, array, nil
+}
+
+// toBitmap converts an array container to a bitmap container
+func (c *roaringContainer) toBitmap() {
+	c.kind, c.bitmap, c.array = roaringBitmap, c.words(), nil
+}
+
+// unrun converts a run container to an array or bitmap container, so that it may be modified
+func (c *roaringContainer) unrun() {
+	if c.kind != roaringRun {
+		return
+	}
+
+	words := c.words()
+	c.kind, c.bitmap, c.runs = roaringBitmap, words, nil
+	if c.card <= roaringArrayMax {
+		c.toArray()
+	}
+}
+
+// valid checks that a decoded container is well formed, with sorted values or non-overlapping runs,
+// and a cardinality which matches its contents
+func (c *roaringContainer) valid() bool {
+	card := 0
+	switch c.kind {
+	case roaringArray:
+		for i := range c.array {
+			if i > 0 && c.array[i] <= c.array[i-1] {
+				return false
+			}
+		}
+		card = len(c.array)
+	case roaringBitmap:
+		for _, w := range c.bitmap {
+			card += bits.OnesCount64(w)
+		}
+	case roaringRun:
+		next := 0
+		for _, r := range c.runs {
+			if int(r.start) < next || int(r.start)+int(r.length) > math.MaxUint16 {
+				return false
+			}
+
+			next = int(r.start) + int(r.length) + 1
+			card += int(r.length) + 1
+		}
+	}
+
+	return card == c.card
+}
+
+// words returns a new bitmap holding the values in the container
+func (c *roaringContainer) words() []uint64 {
+	words := make([]uint64, roaringBitmapWords)
+	if c.kind == roaringBitmap {
+		copy(words, c.bitmap)
+		return words
+	}
+
+	c.each(func(v uint16) {
+		words[v/64] |= uint64(1) << (v % 64)
+	})
+
+	return words
+}
+
+// roaringReader reads little endian values from a serialized RoaringSet, recording an error if the
+// input is too short
+type roaringReader struct {
+	b   []byte
+	err error
+}
+
+// bytes reads n bytes
+func (r *roaringReader) bytes(n int) []byte {
+	if r.err != nil || len(r.b) < n {
+		r.err = errRoaringCorrupt
+		return make([]byte, n)
+	}
+
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+// uint16 reads a little endian uint16
+func (r *roaringReader) uint16() uint16 {
+	return binary.LittleEndian.Uint16(r.bytes(2))
+}
+
+// uint32 reads a little endian uint32
+func (r *roaringReader) uint32() uint32 {
+	return binary.LittleEndian.Uint32(r.bytes(4))
+}
+
+// uint64 reads a little endian uint64
+func (r *roaringReader) uint64() uint64 {
+	return binary.LittleEndian.Uint64(r.bytes(8))
+}
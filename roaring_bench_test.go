@@ -0,0 +1,53 @@
+package set
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// roaringSparse creates a RoaringSet of n random IDs spread across the whole 32-bit range, so that
+// every container is a small array
+func roaringSparse(seed int64, n int) *RoaringSet {
+	rng := rand.New(rand.NewSource(seed))
+	set := NewRoaring()
+	for i := 0; i < n; i++ {
+		set.Add(rng.Uint32())
+	}
+
+	return set
+}
+
+// BenchmarkRoaringUnionSparse checks the performance of the RoaringSet.Union() method over sparse IDs
+func BenchmarkRoaringUnionSparse(b *testing.B) {
+	s, t := roaringSparse(1, 100000), roaringSparse(2, 100000)
+	b.ResetTimer()
+
+	// Run set.Union() b.N times
+	for i := 0; i < b.N; i++ {
+		s.Union(t)
+	}
+}
+
+// BenchmarkRoaringIntersectionSparse checks the performance of the RoaringSet.Intersection() method over
+// sparse IDs
+func BenchmarkRoaringIntersectionSparse(b *testing.B) {
+	s, t := roaringSparse(1, 100000), roaringSparse(2, 100000)
+	b.ResetTimer()
+
+	// Run set.Intersection() b.N times
+	for i := 0; i < b.N; i++ {
+		s.Intersection(t)
+	}
+}
+
+// BenchmarkRoaringDifferenceSparse checks the performance of the RoaringSet.Difference() method over
+// sparse IDs
+func BenchmarkRoaringDifferenceSparse(b *testing.B) {
+	s, t := roaringSparse(1, 100000), roaringSparse(2, 100000)
+	b.ResetTimer()
+
+	// Run set.Difference() b.N times
+	for i := 0; i < b.N; i++ {
+		s.Difference(t)
+	}
+}
@@ -0,0 +1,310 @@
+package set
+
+import (
+	"bytes"
+	"log"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// TestRoaringAddRemove verifies that the RoaringSet.Add(), RoaringSet.Has() and RoaringSet.Remove()
+// methods are working properly as containers change between array and bitmap representations
+func TestRoaringAddRemove(t *testing.T) {
+	log.Println("TestRoaringAddRemove()")
+
+	// Create a set, and a map to track expected contents
+	set := NewRoaring()
+	expected := make(map[uint32]bool)
+
+	// Randomly add and remove elements, concentrated in a few containers so that some grow large
+	// enough to become bitmaps
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 50000; i++ {
+		v := uint32(r.Intn(3))<<16 | uint32(r.Intn(8192))
+		if i%7 == 0 {
+			v = r.Uint32()
+		}
+
+		if r.Intn(4) == 0 {
+			if ok := set.Remove(v); ok != expected[v] {
+				t.Fatalf("set.Remove(%d) - unexpected result: %t", v, ok)
+			}
+			delete(expected, v)
+			continue
+		}
+
+		if ok := set.Add(v); ok == expected[v] {
+			t.Fatalf("set.Add(%d) - unexpected result: %t", v, ok)
+		}
+		expected[v] = true
+	}
+
+	// Verify contents, in ascending order
+	values := make([]uint32, 0, len(expected))
+	for v := range expected {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i int, j int) bool {
+		return values[i] < values[j]
+	})
+
+	out := set.Enumerate()
+	if len(out) != len(values) || set.Size() != len(values) {
+		t.Fatalf("set.Enumerate() - unexpected size: %d != %d", len(out), len(values))
+	}
+	for i := range values {
+		if out[i] != values[i] || !set.Has(values[i]) {
+			t.Fatalf("set.Enumerate() - unexpected element at %d: %d != %d", i, out[i], values[i])
+		}
+	}
+}
+
+// TestRoaringRankSelect verifies that the RoaringSet.Rank() and RoaringSet.Select() methods are
+// working properly for all kinds of containers
+func TestRoaringRankSelect(t *testing.T) {
+	log.Println("TestRoaringRankSelect()")
+
+	// Create a set with an array container, a bitmap container and a run container
+	set := NewRoaring(1, 5, 9)
+	for i := uint32(0); i < 10000; i += 2 {
+		set.Add(1<<16 | i)
+	}
+	for i := uint32(100); i < 200; i++ {
+		set.Add(2<<16 | i)
+	}
+	set.RunOptimize()
+
+	// Every element's rank is its position, and selecting that position returns the element
+	for i, v := range set.Enumerate() {
+		if rank := set.Rank(v); rank != i {
+			t.Fatalf("set.Rank(%d) - unexpected result: %d", v, rank)
+		}
+		if s, ok := set.Select(i); !ok || s != v {
+			t.Fatalf("set.Select(%d) - unexpected result: %d", i, s)
+		}
+	}
+
+	// Ranks of non-members count all smaller elements
+	if rank := set.Rank(6); rank != 2 {
+		t.Fatalf("set.Rank(6) - unexpected result: %d", rank)
+	}
+	if rank := set.Rank(3 << 16); rank != set.Size() {
+		t.Fatalf("set.Rank() - unexpected result past end: %d", rank)
+	}
+	if _, ok := set.Select(set.Size()); ok {
+		t.Fatalf("set.Select() - expected no result past end")
+	}
+
+	// Run containers are converted back when modified
+	if !set.Remove(2<<16|150) || set.Has(2<<16|150) || !set.Has(2<<16|151) {
+		t.Fatalf("set.Remove() - unexpected result for run container")
+	}
+}
+
+// TestRoaringAlgebra verifies that the RoaringSet set algebra methods are working properly
+func TestRoaringAlgebra(t *testing.T) {
+	log.Println("TestRoaringAlgebra()")
+
+	// Create sets with elements spread across several containers
+	set := NewRoaring(1, 3, 5, 1<<16, 5<<20)
+	other := NewRoaring(1, 2, 6, 5<<20, 1<<31)
+
+	// Create a table of tests and expected results of set algebra
+	var tests = []struct {
+		name   string
+		result *RoaringSet
+		target *RoaringSet
+	}{
+		{"Union", set.Union(other), NewRoaring(1, 2, 3, 5, 6, 1<<16, 5<<20, 1<<31)},
+		{"Intersection", set.Intersection(other), NewRoaring(1, 5<<20)},
+		{"Difference", set.Difference(other), NewRoaring(3, 5, 1<<16)},
+		{"SymmetricDifference", set.SymmetricDifference(other), NewRoaring(2, 3, 5, 6, 1<<16, 1<<31)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+
+		log.Println(test.name, "(", set, ",", other, ") =", test.result)
+	}
+
+	// Verify subsets and conversion
+	if !set.Subset(NewRoaring(1, 5<<20)) || set.Subset(other) {
+		t.Fatalf("set.Subset() - unexpected result")
+	}
+	converted, err := RoaringSetFrom(set.ToSet())
+	if err != nil || !converted.Equal(set) {
+		t.Fatalf("RoaringSetFrom() - unexpected result: %s, %v", converted, err)
+	}
+	if _, err := RoaringSetFrom(New(-1)); err == nil {
+		t.Fatalf("RoaringSetFrom() - expected error for negative element")
+	}
+}
+
+// TestRoaringAlgebraContainers verifies that the RoaringSet set algebra methods are working properly for
+// every combination of container kinds, including arrays which grow into bitmaps
+func TestRoaringAlgebraContainers(t *testing.T) {
+	log.Println("TestRoaringAlgebraContainers()")
+
+	// Create sets of each kind of container, using a fixed seed so that failures are reproducible
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []uint32 {
+		values := make([]uint32, n)
+		for i := range values {
+			values[i] = uint32(rng.Intn(1 << 16))
+		}
+		return values
+	}
+	run := NewRoaring()
+	for v := uint32(1000); v < 30000; v++ {
+		run.Add(v)
+	}
+	run.RunOptimize()
+
+	sets := map[string]*RoaringSet{
+		"sparse": NewRoaring(random(100)...),
+		"array":  NewRoaring(random(3000)...),
+		"other":  NewRoaring(random(3000)...),
+		"bitmap": NewRoaring(random(20000)...),
+		"run":    run,
+	}
+
+	// Create a table of operations, and whether they keep elements only in x, only in y, or in both
+	var ops = []struct {
+		name  string
+		fn    func(*RoaringSet, *RoaringSet) *RoaringSet
+		onlyX bool
+		onlyY bool
+		both  bool
+	}{
+		{"Union", (*RoaringSet).Union, true, true, true},
+		{"Intersection", (*RoaringSet).Intersection, false, false, true},
+		{"Difference", (*RoaringSet).Difference, true, false, false},
+		{"SymmetricDifference", (*RoaringSet).SymmetricDifference, true, true, false},
+	}
+
+	// Compare every operation on every pair of sets against the expected elements
+	for xn, x := range sets {
+		for yn, y := range sets {
+			inX, inY := x.ToSet(), y.ToSet()
+			for _, op := range ops {
+				var expected []uint32
+				for v := uint32(0); v < 1<<16; v++ {
+					hx, hy := inX.Has(v), inY.Has(v)
+					if (hx && !hy && op.onlyX) || (!hx && hy && op.onlyY) || (hx && hy && op.both) {
+						expected = append(expected, v)
+					}
+				}
+
+				result := op.fn(x, y)
+				if values := result.Enumerate(); len(values) != len(expected) || result.Size() != len(expected) {
+					t.Fatalf("set.%s(%s, %s) - unexpected size: %d != %d", op.name, xn, yn, len(values), len(expected))
+				}
+				for i, v := range result.Enumerate() {
+					if v != expected[i] {
+						t.Fatalf("set.%s(%s, %s) - unexpected element: %d != %d", op.name, xn, yn, v, expected[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestRoaringMarshalBinary verifies that a RoaringSet round trips through its serialized form
+func TestRoaringMarshalBinary(t *testing.T) {
+	log.Println("TestRoaringMarshalBinary()")
+
+	// Create a set with many containers of each kind
+	set := NewRoaring()
+	for k := uint32(0); k < 6; k++ {
+		for i := uint32(0); i < 5000; i += 1 + k%2 {
+			set.Add(k<<16 | i)
+		}
+	}
+
+	// Create a table of sets to serialize, with and without run containers
+	runs := set.Clone()
+	runs.RunOptimize()
+
+	var tests = []*RoaringSet{
+		NewRoaring(),
+		NewRoaring(1, 2, 3),
+		set,
+		runs,
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		b, err := test.MarshalBinary()
+		if err != nil {
+			t.Fatalf("set.MarshalBinary() - unexpected error: %v", err)
+		}
+
+		out := NewRoaring(42)
+		if err := out.UnmarshalBinary(b); err != nil {
+			t.Fatalf("set.UnmarshalBinary() - unexpected error: %v", err)
+		}
+		if !out.Equal(test) {
+			t.Fatalf("set.UnmarshalBinary() - sets not equal")
+		}
+
+		// Serialization is deterministic
+		if b2, _ := out.MarshalBinary(); !bytes.Equal(b, b2) {
+			t.Fatalf("set.MarshalBinary() - serialized forms not equal after round trip")
+		}
+	}
+
+	// Run containers are smaller
+	full, _ := set.MarshalBinary()
+	compact, _ := runs.MarshalBinary()
+	if len(compact) >= len(full) {
+		t.Fatalf("set.RunOptimize() - serialized form not smaller: %d >= %d", len(compact), len(full))
+	}
+
+	// Truncated input is rejected
+	if err := NewRoaring().UnmarshalBinary(full[:len(full)-1]); err == nil {
+		t.Fatalf("set.UnmarshalBinary() - expected error for truncated input")
+	}
+}
+
+// TestRoaringPortableFormat verifies that a RoaringSet decodes a set serialized by another Roaring
+// implementation
+func TestRoaringPortableFormat(t *testing.T) {
+	log.Println("TestRoaringPortableFormat()")
+
+	// Serialized form of { 1 2 3 } with an array container, without run containers
+	b := []byte{
+		0x3a, 0x30, 0x00, 0x00, // Cookie
+		0x01, 0x00, 0x00, 0x00, // Number of containers
+		0x00, 0x00, 0x02, 0x00, // Key and cardinality - 1
+		0x10, 0x00, 0x00, 0x00, // Offset
+		0x01, 0x00, 0x02, 0x00, 0x03, 0x00, // Values
+	}
+
+	set := NewRoaring()
+	if err := set.UnmarshalBinary(b); err != nil {
+		t.Fatalf("set.UnmarshalBinary() - unexpected error: %v", err)
+	}
+	if !set.Equal(NewRoaring(1, 2, 3)) {
+		t.Fatalf("set.UnmarshalBinary() - unexpected result: %s", set)
+	}
+
+	// Serialized form of { 1 2 3 } with a run container
+	b = []byte{
+		0x3b, 0x30, 0x00, 0x00, // Cookie and number of containers - 1
+		0x01,                   // Run container bitset
+		0x00, 0x00, 0x02, 0x00, // Key and cardinality - 1
+		0x01, 0x00, // Number of runs
+		0x01, 0x00, 0x02, 0x00, // Run start and length - 1
+	}
+
+	if err := set.UnmarshalBinary(b); err != nil {
+		t.Fatalf("set.UnmarshalBinary() - unexpected error: %v", err)
+	}
+	if !set.Equal(NewRoaring(1, 2, 3)) {
+		t.Fatalf("set.UnmarshalBinary() - unexpected result with runs: %s", set)
+	}
+}
@@ -2,7 +2,9 @@ package set
 
 import (
 	"fmt"
+	"iter"
 	"sync"
+	"unsafe"
 )
 
 // Set represents an unordered collection of unique values
@@ -11,9 +13,15 @@ type Set struct {
 	mutex sync.RWMutex
 	// Empty struct consumes no memory, so we just use the map keys
 	m map[interface{}]struct{}
+	// Options used when encoding and decoding the set as JSON
+	jsonOpts JSONOptions
+	// Subscribers notified of every element added or removed
+	subs []*subscription
 }
 
-// New creates a new Set, and initializes its internal map, optionally adding initial elements to the set
+// New creates a new Set, and initializes its internal map, optionally adding initial elements to the set.
+// Initial elements which are not hashable, such as slices or maps, are silently discarded, so the new set
+// may hold fewer elements than were specified.  Use TryNew to receive an error instead.
 func New(values ...interface{}) *Set {
 	// Initialize set
 	s := Set{
@@ -28,21 +36,83 @@ func New(values ...interface{}) *Set {
 	return &s
 }
 
+// TryNew creates a new Set containing the specified elements, returning an error if any element is not
+// hashable, rather than discarding it as New does
+func TryNew(values ...interface{}) (*Set, error) {
+	s := New()
+	for _, v := range values {
+		if _, err := s.add(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
 // Add inserts a new element into the set, returning true if the element was newly added, or false
-// if it already existed
+// if it already existed.  The check and insert happen atomically, so when multiple goroutines add
+// the same element, exactly one of them will see true.
+//
+// If the element is not hashable, such as a slice or map, it is discarded, and Add returns false just
+// as if it already existed.  Use TryAdd to tell the two cases apart.
 func (s *Set) Add(value interface{}) bool {
-	// Check existence
-	found := s.Has(value)
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	added, _ := s.add(value)
+	return added
+}
 
+// AddAll inserts all elements into the set within a single critical section, returning a slice which
+// reports, for each element, whether or not it was newly added.  Elements which are not hashable are
+// not added.
+func (s *Set) AddAll(values ...interface{}) []bool {
 	// Lock set for write
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// Add value to set
-	s.m[value] = struct{}{}
+	// Add all values to set, recording results
+	results := make([]bool, len(values))
+	for i, v := range values {
+		results[i], _ = s.add(v)
+	}
+
+	return results
+}
 
-	// Return inverse, so true if element already existed
-	return !found
+// AddIfAbsent inserts a new element into the set only if it is not already present, returning true
+// if the element already existed, or false if it was newly added by this call.  It is the inverse
+// of Add, for callers which are interested in whether or not another goroutine won the insert.  If
+// the element is not hashable, it is not added, and AddIfAbsent returns false with an error.
+func (s *Set) AddIfAbsent(value interface{}) (bool, error) {
+	added, err := s.TryAdd(value)
+	if err != nil {
+		return false, err
+	}
+
+	return !added, nil
+}
+
+// TryAdd inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  Unlike Add, if the element is not hashable, such as a slice or map, or a
+// struct or array containing one, TryAdd returns an error describing it.  Use NewWith to store such
+// elements in a CustomSet.
+func (s *Set) TryAdd(value interface{}) (bool, error) {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.add(value)
+}
+
+// All returns an iterator over all elements in the set, in no particular order, for use with a
+// range loop.  The set is locked for read while iteration is in progress, so the loop body sees a
+// consistent view of the set, and must not modify the set.
+func (s *Set) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		s.Each(yield)
+	}
 }
 
 // Pair represents a pair of elements created from a cartesian product
@@ -58,18 +128,24 @@ func (p *Pair) String() string {
 
 // CartesianProduct returns a set containing ordered pairs of every permutation between two sets
 func (s *Set) CartesianProduct(t *Set) *Set {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
 	// Create a set of ordered pair permutations between the sets
-	cpSet := New()
+	cpSet := &Set{
+		m: make(map[interface{}]struct{}, len(s.m)*len(t.m)),
+	}
 
 	// Enumerate the source set
-	for _, x := range s.Enumerate() {
+	for x := range s.m {
 		// Enumerate the target set
-		for _, y := range t.Enumerate() {
+		for y := range t.m {
 			// Create pair, insert elements, insert into set
-			cpSet.Add(Pair{
+			cpSet.m[Pair{
 				X: x,
 				Y: y,
-			})
+			}] = struct{}{}
 		}
 	}
 
@@ -78,10 +154,16 @@ func (s *Set) CartesianProduct(t *Set) *Set {
 
 // Clone copies the current set into a new, identical set
 func (s *Set) Clone() *Set {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	// Copy set into a new set
-	outSet := New()
-	for _, v := range s.Enumerate() {
-		outSet.Add(v)
+	outSet := &Set{
+		m: make(map[interface{}]struct{}, len(s.m)),
+	}
+	for k := range s.m {
+		outSet.m[k] = struct{}{}
 	}
 
 	return outSet
@@ -90,30 +172,39 @@ func (s *Set) Clone() *Set {
 // Difference returns a set containing all elements present in this set, but without any elements
 // present in the parameter set
 func (s *Set) Difference(t *Set) *Set {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
 	// Create a set of differences between the sets
 	diffSet := New()
 
-	// Enumerate and check all elements in the current set
-	for _, e := range s.Enumerate() {
-		found := false
-
-		// Check if element is present in parameter set
-		for _, p := range t.Enumerate() {
-			// Element found
-			if e == p {
-				found = true
-			}
-		}
-
-		// If element was not found, add it to diff set
-		if !found {
-			diffSet.Add(e)
+	// Enumerate all elements in the current set, and add those not present in the parameter set
+	for k := range s.m {
+		if _, ok := t.m[k]; !ok {
+			diffSet.m[k] = struct{}{}
 		}
 	}
 
 	return diffSet
 }
 
+// Each applies a function over all elements of the set, in no particular order, until the function
+// returns false.  The set is locked for read while the function is applied, so the function sees a
+// consistent view of the set, and must not modify the set.
+func (s *Set) Each(fn func(interface{}) bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Enumerate all elements, stopping early if requested
+	for k := range s.m {
+		if !fn(k) {
+			return
+		}
+	}
+}
+
 // Enumerate returns an unordered slice of all elements in the set
 func (s *Set) Enumerate() []interface{} {
 	// Lock set for read
@@ -121,7 +212,7 @@ func (s *Set) Enumerate() []interface{} {
 	defer s.mutex.RUnlock()
 
 	// Gather all values into a slice
-	values := make([]interface{}, 0)
+	values := make([]interface{}, 0, len(s.m))
 	for k := range s.m {
 		values = append(values, k)
 	}
@@ -131,66 +222,75 @@ func (s *Set) Enumerate() []interface{} {
 
 // Equal returns whether or not two sets have the same length and no differences, meaning they are equal
 func (s *Set) Equal(t *Set) bool {
-	return s.Size() == t.Size() && s.Difference(t).Size() == 0
+	return s.Size() == t.Size() && t.Subset(s)
 }
 
 // Filter applies a function over all elements of the set, and returns all elements which return true
-// when the function is applied
+// when the function is applied.  Like Each, the set is locked for read while the function is applied,
+// without copying its elements, so the function must not call any method of the set.
 func (s *Set) Filter(fn func(interface{}) bool) *Set {
 	// Create a set to return with elements which match filter function
 	filterSet := New()
 
 	// Enumerate all elements and apply the function
-	for _, e := range s.Enumerate() {
+	s.Each(func(e interface{}) bool {
 		// Apply the function, add elements which it matches
 		if fn(e) {
-			filterSet.Add(e)
+			filterSet.m[e] = struct{}{}
 		}
-	}
+		return true
+	})
 
 	return filterSet
 }
 
-// Has checks for membership of an element in the set
+// Has checks for membership of an element in the set.  Elements which are not hashable are never members.
 func (s *Set) Has(value interface{}) bool {
 	// Lock set for read
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
 	// Check for value
-	if _, ok := s.m[value]; ok {
-		// Value found
-		return true
-	}
-
-	// Value not found
-	return false
+	found, _ := s.probe(value)
+	return found
 }
 
 // Intersection returns a set containing all elements present in both the current set and the parameter set
 func (s *Set) Intersection(t *Set) *Set {
-	// Copy current set, create a set of intersections between the sets
-	intSet := s.Clone()
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Enumerate the smaller of the two sets
+	small, large := s.m, t.m
+	if len(small) > len(large) {
+		small, large = large, small
+	}
 
-	// Get all differences between the sets
-	for _, d := range s.Difference(t).Enumerate() {
-		// Remove all different elements
-		intSet.Remove(d)
+	// Create a set of intersections between the sets
+	intSet := New()
+	for k := range small {
+		if _, ok := large[k]; ok {
+			intSet.m[k] = struct{}{}
+		}
 	}
 
 	return intSet
 }
 
-// Map applies a function over all elements of the set, and returns the resulting set
+// Map applies a function over all elements of the set, and returns the resulting set.  Like Each, the
+// set is locked for read while the function is applied, without copying its elements, so the function
+// must not call any method of the set.  Results which are not hashable are not added to the resulting set.
 func (s *Set) Map(fn func(interface{}) interface{}) *Set {
 	// Create a set to return with function applied
 	mapSet := New()
 
 	// Enumerate all elements and apply the function
-	for _, e := range s.Enumerate() {
+	s.Each(func(e interface{}) bool {
 		// Apply the function, capture result
-		mapSet.Add(fn(e))
-	}
+		mapSet.add(fn(e))
+		return true
+	})
 
 	return mapSet
 }
@@ -241,29 +341,64 @@ func (s *Set) PowerSet() *Set {
 	return powerSet(s.Clone())
 }
 
-// Reduce applies a function over all elements of the set, accumulating the results into a final result value
+// Reduce applies a function over all elements of the set, accumulating the results into a final result value.
+// Like Each, the set is locked for read while the function is applied, without copying its elements, so the
+// function must not call any method of the set.
 func (s *Set) Reduce(value interface{}, fn func(interface{}, interface{}) interface{}) interface{} {
 	// Enumerate all elements and apply the function
-	for _, e := range s.Enumerate() {
+	s.Each(func(e interface{}) bool {
 		value = fn(value, e)
-	}
+		return true
+	})
 
 	return value
 }
 
-// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist.
+// The check and removal happen atomically, so when multiple goroutines remove the same element, exactly
+// one of them will see true.
 func (s *Set) Remove(value interface{}) bool {
-	// Check existence
-	found := s.Has(value)
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.remove(value)
+}
 
+// RemoveAll destroys all elements in the set within a single critical section, returning a slice which
+// reports, for each element, whether or not it was destroyed
+func (s *Set) RemoveAll(values ...interface{}) []bool {
 	// Lock set for write
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// Remove value from set
-	delete(s.m, value)
+	// Remove all values from set, recording results
+	results := make([]bool, len(values))
+	for i, v := range values {
+		results[i] = s.remove(v)
+	}
 
-	return found
+	return results
+}
+
+// Swap atomically replaces an element in the set with a new element, returning true if the old element
+// was present and has been replaced, or false if the old element did not exist or the new element is
+// not hashable, in which case the set is left unchanged
+func (s *Set) Swap(oldValue interface{}, newValue interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Only swap if the new value can be added, and the old value exists
+	if _, hashable := s.probe(newValue); !hashable {
+		return false
+	}
+	if !s.remove(oldValue) {
+		return false
+	}
+
+	s.add(newValue)
+	return true
 }
 
 // Size returns the size or cardinality of this set
@@ -275,36 +410,23 @@ func (s *Set) Size() int {
 	return len(s.m)
 }
 
-// String returns a string representation of this set
+// String returns a string representation of this set, with elements in canonical sorted order.  It
+// is equivalent to formatting the set with the %v verb.
 func (s *Set) String() string {
-	// Print identifier
-	str := "{ "
-
-	// Check for empty set, print symbol if empty
-	if s.Size() == 0 {
-		return str + "Ø }"
-	}
-
-	// Print all elements
-	for k := range s.m {
-		// Print pairs separately
-		if pair, ok := k.(Pair); ok {
-			str = str + fmt.Sprintf("%v ", pair.String())
-		} else {
-			str = str + fmt.Sprintf("%v ", k)
-		}
-	}
-
-	return str + "}"
+	return fmt.Sprintf("%v", s)
 }
 
 // Subset determines if a parameter set is a subset of elements within this set, returning true if it
 // is a subset, or false if it is not
 func (s *Set) Subset(t *Set) bool {
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
 	// Check if all elements in the parameter set are contained within the set
-	for _, v := range t.Enumerate() {
+	for k := range t.m {
 		// Check if element is contained, if not, return false
-		if !s.Has(v) {
+		if _, ok := s.m[k]; !ok {
 			return false
 		}
 	}
@@ -315,19 +437,120 @@ func (s *Set) Subset(t *Set) bool {
 // SymmetricDifference returns a set containing all elements which are not shared between this set
 // and the parameter set
 func (s *Set) SymmetricDifference(t *Set) *Set {
-	return s.Difference(t).Union(t.Difference(s))
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
+
+	// Create a set of symmetric differences between the sets
+	symSet := New()
+
+	// Add elements from each set which are not present in the other
+	for k := range s.m {
+		if _, ok := t.m[k]; !ok {
+			symSet.m[k] = struct{}{}
+		}
+	}
+	for k := range t.m {
+		if _, ok := s.m[k]; !ok {
+			symSet.m[k] = struct{}{}
+		}
+	}
+
+	return symSet
 }
 
 // Union returns a set containing all elements present in this set, as well as all elements present
 // in the parameter set
 func (s *Set) Union(t *Set) *Set {
-	// Clone the current set into a new set
-	outSet := s.Clone()
+	// Lock both sets for read
+	unlock := rlockPair(&s.mutex, &t.mutex)
+	defer unlock()
 
-	// Enumerate and add all elements from the parameter set
-	for _, e := range t.Enumerate() {
-		outSet.Add(e)
+	// Copy all elements from both sets into a new set
+	outSet := &Set{
+		m: make(map[interface{}]struct{}, len(s.m)+len(t.m)),
+	}
+	for k := range s.m {
+		outSet.m[k] = struct{}{}
+	}
+	for k := range t.m {
+		outSet.m[k] = struct{}{}
 	}
 
 	return outSet
 }
+
+// rlockPair locks the mutexes of two sets for read, returning a function which unlocks them both.
+// Mutexes are always locked in the same order, so that two goroutines locking the same pair of sets
+// in opposite order cannot deadlock, and a set paired with itself is only locked once.
+func rlockPair(s *sync.RWMutex, t *sync.RWMutex) func() {
+	// Lock a set paired with itself only once
+	if s == t {
+		s.RLock()
+		return s.RUnlock
+	}
+
+	// Order the mutexes by address
+	if uintptr(unsafe.Pointer(t)) < uintptr(unsafe.Pointer(s)) {
+		s, t = t, s
+	}
+
+	s.RLock()
+	t.RLock()
+	return func() {
+		t.RUnlock()
+		s.RUnlock()
+	}
+}
+
+// add inserts a value into the set, returning true if it was newly added, or an error if the value is
+// not hashable.  The caller must hold the write lock.
+func (s *Set) add(value interface{}) (bool, error) {
+	// Check existence
+	found, hashable := s.probe(value)
+	if !hashable {
+		return false, errUnhashable(value)
+	}
+	if found {
+		return false, nil
+	}
+
+	// Add value to set, and notify subscribers
+	s.m[value] = struct{}{}
+	s.notify(EventAdd, value)
+	return true, nil
+}
+
+// probe checks for membership of a value, and reports whether or not the value could be hashed,
+// rather than panicking if it could not.  The caller must hold the read or write lock.
+func (s *Set) probe(value interface{}) (found bool, hashable bool) {
+	return probeMap(s.m, value)
+}
+
+// probeMap checks for membership of a value in the map of a Set or UnsyncSet, and reports whether or
+// not the value could be hashed, rather than panicking if it could not
+func probeMap(m map[interface{}]struct{}, value interface{}) (found bool, hashable bool) {
+	// Hashing an unhashable value panics, so recover
+	defer func() {
+		if recover() != nil {
+			hashable = false
+		}
+	}()
+
+	_, found = m[value]
+	return found, true
+}
+
+// remove destroys a value in the set, returning true if it existed.  The caller must hold the write
+// lock.
+func (s *Set) remove(value interface{}) bool {
+	// Check existence
+	if found, _ := s.probe(value); !found {
+		return false
+	}
+
+	// Remove value from set, and notify subscribers
+	delete(s.m, value)
+	s.notify(EventRemove, value)
+	return true
+}
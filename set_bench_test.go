@@ -35,6 +35,27 @@ func BenchmarkCartesianProductLarge(b *testing.B) {
 	benchmarkCartesianProduct(b.N, New(1, 2, 3, 4, 5, 6, 7, 8, 9), New(9, 8, 7, 6, 5, 4, 3, 2, 1))
 }
 
+// benchmarkCartesianPairs checks the performance of the set.CartesianPairs() method
+func benchmarkCartesianPairs(n int, s *Set, t *Set) {
+	// Run set.CartesianPairs() n times, consuming all pairs
+	for i := 0; i < n; i++ {
+		for range s.CartesianPairs(t) {
+		}
+	}
+}
+
+// BenchmarkCartesianPairsSmall checks the performance of the set.CartesianPairs() method
+// over a small data set
+func BenchmarkCartesianPairsSmall(b *testing.B) {
+	benchmarkCartesianPairs(b.N, New(1, 2), New(2, 1))
+}
+
+// BenchmarkCartesianPairsLarge checks the performance of the set.CartesianPairs() method
+// over a large data set
+func BenchmarkCartesianPairsLarge(b *testing.B) {
+	benchmarkCartesianPairs(b.N, New(1, 2, 3, 4, 5, 6, 7, 8, 9), New(9, 8, 7, 6, 5, 4, 3, 2, 1))
+}
+
 // benchmarkClone checks the performance of the set.Clone() method
 func benchmarkClone(n int, s *Set) {
 	// Run set.Clone() n times
@@ -75,6 +96,28 @@ func BenchmarkDifferenceLarge(b *testing.B) {
 	benchmarkDifference(b.N, New(1, 2, 3, 4, 5, 6, 7, 8, 9), New(9, 8, 7, 6, 5, 4, 3, 2, 1))
 }
 
+// benchmarkEach checks the performance of the set.Each() method
+func benchmarkEach(n int, s *Set) {
+	// Run set.Each() n times
+	for i := 0; i < n; i++ {
+		s.Each(func(interface{}) bool {
+			return true
+		})
+	}
+}
+
+// BenchmarkEachSmall checks the performance of the set.Each() method
+// over a small data set
+func BenchmarkEachSmall(b *testing.B) {
+	benchmarkEach(b.N, New(1, 2))
+}
+
+// BenchmarkEachLarge checks the performance of the set.Each() method
+// over a large data set
+func BenchmarkEachLarge(b *testing.B) {
+	benchmarkEach(b.N, New(1, 2, 3, 4, 5, 6, 7, 8, 9))
+}
+
 // benchmarkEnumerate checks the performance of the set.Enumerate() method
 func benchmarkEnumerate(n int, s *Set) {
 	// Run set.Enumerate() n times
@@ -319,3 +362,25 @@ func BenchmarkUnionSmall(b *testing.B) {
 func BenchmarkUnionLarge(b *testing.B) {
 	benchmarkUnion(b.N, New(1, 2, 3, 4, 5, 6, 7, 8, 9), New(9, 8, 7, 6, 5, 4, 3, 2, 1))
 }
+
+// BenchmarkUnsyncAdd checks the performance of the UnsyncSet.Add() method
+func BenchmarkUnsyncAdd(b *testing.B) {
+	// Create a new set
+	set := NewUnsync()
+
+	// Run set.Add() b.N times
+	for i := 0; i < b.N; i++ {
+		set.Add(i)
+	}
+}
+
+// BenchmarkUnsyncHas checks the performance of the UnsyncSet.Has() method
+func BenchmarkUnsyncHas(b *testing.B) {
+	// Create a new set
+	set := NewUnsync()
+
+	// Run set.Has() b.N times
+	for i := 0; i < b.N; i++ {
+		set.Has(i)
+	}
+}
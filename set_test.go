@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -44,6 +45,134 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+// TestAddAll verifies that the set.AddAll() method is working properly
+func TestAddAll(t *testing.T) {
+	log.Println("TestAddAll()")
+
+	// Create a set, add some initial values
+	set := New(1, 3, 5)
+
+	// Add new, existing and repeated elements, verify per-element results
+	results := set.AddAll(2, 3, 4, 2)
+	expected := []bool{true, false, true, false}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Fatalf("set.AddAll() - unexpected result at %d: %t", i, results[i])
+		}
+	}
+
+	if !set.Equal(New(1, 2, 3, 4, 5)) {
+		t.Fatalf("set.AddAll() - unexpected set: %s", set)
+	}
+
+	log.Println(set, results)
+}
+
+// TestAddConcurrent verifies that the set.Add() and set.Remove() methods report exactly one winner
+// when many goroutines add and remove the same element
+func TestAddConcurrent(t *testing.T) {
+	log.Println("TestAddConcurrent()")
+
+	// Create a set
+	set := New()
+
+	const goroutines = 16
+	const elements = 1000
+
+	// run calls fn for every element from many goroutines, counting the number of calls which
+	// returned true
+	run := func(fn func(interface{}) bool) int {
+		var mu sync.Mutex
+		count := 0
+
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				n := 0
+				for j := 0; j < elements; j++ {
+					if fn(j) {
+						n++
+					}
+				}
+
+				mu.Lock()
+				count += n
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		return count
+	}
+
+	// Each element should be added exactly once, and removed exactly once
+	if added := run(set.Add); added != elements {
+		t.Fatalf("set.Add() - unexpected number of winners: %d", added)
+	}
+	if removed := run(set.Remove); removed != elements {
+		t.Fatalf("set.Remove() - unexpected number of winners: %d", removed)
+	}
+}
+
+// TestAddIfAbsent verifies that the set.AddIfAbsent() method is working properly
+func TestAddIfAbsent(t *testing.T) {
+	log.Println("TestAddIfAbsent()")
+
+	// Create a set, add some initial values
+	set := New(1, 3, 5)
+
+	// Existing elements are reported as present, new elements are added
+	if existed, err := set.AddIfAbsent(1); !existed || err != nil {
+		t.Fatalf("set.AddIfAbsent(1) - unexpected result: %t, %v", existed, err)
+	}
+	if existed, err := set.AddIfAbsent(2); existed || err != nil || !set.Has(2) {
+		t.Fatalf("set.AddIfAbsent(2) - unexpected result: %t, %v", existed, err)
+	}
+
+	// Unhashable elements are neither reported as present, nor added
+	if existed, err := set.AddIfAbsent([]int{1}); existed || err == nil || set.Size() != 4 {
+		t.Fatalf("set.AddIfAbsent([1]) - unexpected result: %t, %v", existed, err)
+	}
+}
+
+// TestAll verifies that the set.All() method is working properly
+func TestAll(t *testing.T) {
+	log.Println("TestAll()")
+
+	// Create a set, add some initial values
+	set := New(1, 3, 5, 7, 9)
+
+	// Range over all elements, verifying each is a member of the set
+	count := 0
+	for v := range set.All() {
+		if !New(1, 3, 5, 7, 9).Has(v) {
+			t.Fatalf("set.All() - unexpected element: %v", v)
+		}
+		count++
+	}
+	if count != set.Size() {
+		t.Fatalf("set.All() - unexpected number of elements: %d", count)
+	}
+
+	// Break out of the loop early
+	count = 0
+	for range set.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Fatalf("set.All() - unexpected number of elements after break: %d", count)
+	}
+
+	// The set is unlocked once iteration stops, so it may be modified
+	set.Add(11)
+}
+
 // TestCartesianProduct verifies that the set.CartesianProduct() method is working properly
 func TestCartesianProduct(t *testing.T) {
 	log.Println("TestCartesianProduct()")
@@ -76,6 +205,60 @@ func TestCartesianProduct(t *testing.T) {
 	}
 }
 
+// TestCallbacksConsistent verifies that the functions passed to set.Filter(), set.Map() and set.Reduce()
+// see a consistent view of the set while it is modified concurrently
+func TestCallbacksConsistent(t *testing.T) {
+	log.Println("TestCallbacksConsistent()")
+
+	// Create a set of stable elements, which a writer never touches
+	s := New()
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+
+	// Add and remove other elements concurrently
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			s.Add(1000 + i%10)
+			s.Remove(1000 + (i+5)%10)
+		}
+	}()
+
+	// Every stable element is seen exactly once, on every pass
+	for i := 0; i < 100; i++ {
+		stable := func(v interface{}) bool {
+			return v.(int) < 100
+		}
+		if n := s.Filter(stable).Size(); n != 100 {
+			t.Fatalf("set.Filter() - unexpected number of stable elements: %d", n)
+		}
+		if n := s.Map(func(v interface{}) interface{} { return stable(v) }).Size(); n > 2 {
+			t.Fatalf("set.Map() - unexpected result size: %d", n)
+		}
+		n := s.Reduce(0, func(a interface{}, v interface{}) interface{} {
+			if stable(v) {
+				return a.(int) + 1
+			}
+			return a
+		})
+		if n != 100 {
+			t.Fatalf("set.Reduce() - unexpected number of stable elements: %v", n)
+		}
+	}
+
+	close(done)
+	<-stopped
+}
+
 // TestClone verifies that the set.Clone() method is working properly
 func TestClone(t *testing.T) {
 	log.Println("TestClone()")
@@ -137,6 +320,42 @@ func TestDifference(t *testing.T) {
 	}
 }
 
+// TestEach verifies that the set.Each() method is working properly
+func TestEach(t *testing.T) {
+	log.Println("TestEach()")
+
+	// Create a table of tests and expected number of elements visited before stopping
+	var tests = []struct {
+		source *Set
+		stop   int
+		count  int
+	}{
+		// Visit all elements
+		{New(1, 3, 5), -1, 3},
+		// Stop after the first element
+		{New(1, 3, 5), 1, 1},
+		// Stop after the second element
+		{New(1, 3, 5), 2, 2},
+		// Empty set
+		{New(), 1, 0},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		count := 0
+		test.source.Each(func(v interface{}) bool {
+			count++
+			return count != test.stop
+		})
+
+		if count != test.count {
+			t.Fatalf("set.Each() - unexpected number of elements: %d", count)
+		}
+
+		log.Println("each(", test.source, ") ->", count)
+	}
+}
+
 // TestEnumerate verifies that the set.Enumerate() method is working properly
 func TestEnumerate(t *testing.T) {
 	log.Println("TestEnumerate()")
@@ -502,6 +721,29 @@ func TestRemove(t *testing.T) {
 	}
 }
 
+// TestRemoveAll verifies that the set.RemoveAll() method is working properly
+func TestRemoveAll(t *testing.T) {
+	log.Println("TestRemoveAll()")
+
+	// Create a set, add some initial values
+	set := New(1, 3, 5)
+
+	// Remove existing, missing and repeated elements, verify per-element results
+	results := set.RemoveAll(1, 2, 5, 1)
+	expected := []bool{true, false, true, false}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Fatalf("set.RemoveAll() - unexpected result at %d: %t", i, results[i])
+		}
+	}
+
+	if !set.Equal(New(3)) {
+		t.Fatalf("set.RemoveAll() - unexpected set: %s", set)
+	}
+
+	log.Println(set, results)
+}
+
 // TestSize verifies that the set.Size() method is working properly
 func TestSize(t *testing.T) {
 	log.Println("TestSize()")
@@ -576,6 +818,41 @@ func TestSubset(t *testing.T) {
 	}
 }
 
+// TestSwap verifies that the set.Swap() method is working properly
+func TestSwap(t *testing.T) {
+	log.Println("TestSwap()")
+
+	// Create a set, add some initial values
+	set := New(1, 3, 5)
+
+	// Create a table of tests and expected results for swapping elements
+	var tests = []struct {
+		old    interface{}
+		new    interface{}
+		result bool
+		target *Set
+	}{
+		// Existing item
+		{1, 2, true, New(2, 3, 5)},
+		// Non-existant item
+		{1, 4, false, New(2, 3, 5)},
+		// Swap onto existing item
+		{2, 3, true, New(3, 5)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if ok := set.Swap(test.old, test.new); ok != test.result {
+			t.Fatalf("set.Swap(%v, %v) - unexpected result: %t", test.old, test.new, ok)
+		}
+		if !set.Equal(test.target) {
+			t.Fatalf("set.Swap(%v, %v) - sets not equal: %s != %s", test.old, test.new, set, test.target)
+		}
+
+		log.Println(test.old, "⇄", test.new, "=", set)
+	}
+}
+
 // TestSymmetricDifference verifies that the set.SymmetricDifference() method is working properly
 func TestSymmetricDifference(t *testing.T) {
 	log.Println("TestSymmetricDifference()")
@@ -0,0 +1,658 @@
+package set
+
+import (
+	"sort"
+	"sync"
+)
+
+// SortedSet represents an ordered collection of unique values, ordered by a user-supplied comparison
+// function.  It is backed by an AVL tree, where each node tracks the size of its subtree so that
+// elements may be ranked and selected by position.
+type SortedSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Comparison function which determines the order of elements
+	compare func(interface{}, interface{}) int
+	// Root node of the tree
+	root *sortedNode
+}
+
+// sortedNode is a single node in the AVL tree backing a SortedSet
+type sortedNode struct {
+	value  interface{}
+	left   *sortedNode
+	right  *sortedNode
+	height int
+	size   int
+}
+
+// NewSorted creates a new SortedSet ordered by the comparison function, optionally adding initial
+// elements to the set.  The comparison function must return a negative number if a < b, zero if
+// a == b, or a positive number if a > b.
+func NewSorted(compare func(a interface{}, b interface{}) int, values ...interface{}) *SortedSet {
+	// Initialize set
+	s := SortedSet{
+		compare: compare,
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed
+func (s *SortedSet) Add(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var added bool
+	s.root = s.insert(s.root, value, &added)
+	return added
+}
+
+// Ceiling returns the smallest element in the set which is greater than or equal to the parameter
+// value, and whether or not such an element exists
+func (s *SortedSet) Ceiling(value interface{}) (interface{}, bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Walk the tree, remembering the last node greater than the value
+	var found *sortedNode
+	for n := s.root; n != nil; {
+		c := s.compare(value, n.value)
+		switch {
+		case c == 0:
+			return n.value, true
+		case c < 0:
+			found = n
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+
+	if found == nil {
+		return nil, false
+	}
+
+	return found.value, true
+}
+
+// Clone copies the current set into a new, identical set
+func (s *SortedSet) Clone() *SortedSet {
+	// Copy set into a new set, which shares the same comparison function
+	return NewSorted(s.compare, s.Enumerate()...)
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *SortedSet) Difference(t *SortedSet) *SortedSet {
+	// Merge both ordered sets, keeping elements only present in the current set
+	return s.merge(t, func(inS bool, inT bool) bool {
+		return inS && !inT
+	})
+}
+
+// Enumerate returns a slice of all elements in the set, in ascending order
+func (s *SortedSet) Enumerate() []interface{} {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice, using an in-order walk of the tree
+	values := make([]interface{}, 0, sortedSize(s.root))
+	sortedWalk(s.root, func(v interface{}) {
+		values = append(values, v)
+	})
+
+	return values
+}
+
+// Equal returns whether or not two sets have the same length and no differences, meaning they are equal
+func (s *SortedSet) Equal(t *SortedSet) bool {
+	return s.Size() == t.Size() && s.Difference(t).Size() == 0
+}
+
+// Filter applies a function over all elements of the set, and returns all elements which return true
+// when the function is applied
+func (s *SortedSet) Filter(fn func(interface{}) bool) *SortedSet {
+	// Create a set to return with elements which match filter function
+	filterSet := NewSorted(s.compare)
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, add elements which it matches
+		if fn(e) {
+			filterSet.Add(e)
+		}
+	}
+
+	return filterSet
+}
+
+// Floor returns the largest element in the set which is less than or equal to the parameter value,
+// and whether or not such an element exists
+func (s *SortedSet) Floor(value interface{}) (interface{}, bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Walk the tree, remembering the last node less than the value
+	var found *sortedNode
+	for n := s.root; n != nil; {
+		c := s.compare(value, n.value)
+		switch {
+		case c == 0:
+			return n.value, true
+		case c < 0:
+			n = n.left
+		default:
+			found = n
+			n = n.right
+		}
+	}
+
+	if found == nil {
+		return nil, false
+	}
+
+	return found.value, true
+}
+
+// Has checks for membership of an element in the set
+func (s *SortedSet) Has(value interface{}) bool {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Search the tree for value
+	for n := s.root; n != nil; {
+		c := s.compare(value, n.value)
+		switch {
+		case c == 0:
+			return true
+		case c < 0:
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+
+	// Value not found
+	return false
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *SortedSet) Intersection(t *SortedSet) *SortedSet {
+	// Merge both ordered sets, keeping elements present in both sets
+	return s.merge(t, func(inS bool, inT bool) bool {
+		return inS && inT
+	})
+}
+
+// Map applies a function over all elements of the set, and returns the resulting set, which is
+// ordered by the same comparison function as the current set
+func (s *SortedSet) Map(fn func(interface{}) interface{}) *SortedSet {
+	// Create a set to return with function applied
+	mapSet := NewSorted(s.compare)
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, capture result
+		mapSet.Add(fn(e))
+	}
+
+	return mapSet
+}
+
+// Max returns the largest element in the set, and whether or not the set contained any elements
+func (s *SortedSet) Max() (interface{}, bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check for empty set
+	if s.root == nil {
+		return nil, false
+	}
+
+	// Walk to the rightmost node
+	n := s.root
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.value, true
+}
+
+// Min returns the smallest element in the set, and whether or not the set contained any elements
+func (s *SortedSet) Min() (interface{}, bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check for empty set
+	if s.root == nil {
+		return nil, false
+	}
+
+	// Walk to the leftmost node
+	n := s.root
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.value, true
+}
+
+// Range returns a slice of all elements in the set which are greater than or equal to lo, and less
+// than or equal to hi, in ascending order
+func (s *SortedSet) Range(lo interface{}, hi interface{}) []interface{} {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather values within the range, pruning subtrees which fall outside it
+	values := make([]interface{}, 0)
+	var walkRange func(n *sortedNode)
+	walkRange = func(n *sortedNode) {
+		if n == nil {
+			return
+		}
+
+		cLo := s.compare(n.value, lo)
+		cHi := s.compare(n.value, hi)
+
+		if cLo > 0 {
+			walkRange(n.left)
+		}
+		if cLo >= 0 && cHi <= 0 {
+			values = append(values, n.value)
+		}
+		if cHi < 0 {
+			walkRange(n.right)
+		}
+	}
+	walkRange(s.root)
+
+	return values
+}
+
+// Rank returns the number of elements in the set which are less than the parameter value.  If the
+// value is a member of the set, this is its zero-based position in ascending order.
+func (s *SortedSet) Rank(value interface{}) int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Walk the tree, counting all nodes to the left of the value
+	rank := 0
+	for n := s.root; n != nil; {
+		c := s.compare(value, n.value)
+		switch {
+		case c == 0:
+			return rank + sortedSize(n.left)
+		case c < 0:
+			n = n.left
+		default:
+			rank += sortedSize(n.left) + 1
+			n = n.right
+		}
+	}
+
+	return rank
+}
+
+// Reduce applies a function over all elements of the set in ascending order, accumulating the results
+// into a final result value
+func (s *SortedSet) Reduce(value interface{}, fn func(interface{}, interface{}) interface{}) interface{} {
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		value = fn(value, e)
+	}
+
+	return value
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *SortedSet) Remove(value interface{}) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var removed bool
+	s.root = s.delete(s.root, value, &removed)
+	return removed
+}
+
+// Select returns the element at the zero-based position i in ascending order, and whether or not
+// such an element exists
+func (s *SortedSet) Select(i int) (interface{}, bool) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check bounds
+	if i < 0 || i >= sortedSize(s.root) {
+		return nil, false
+	}
+
+	// Walk the tree, using subtree sizes to find the position
+	n := s.root
+	for {
+		l := sortedSize(n.left)
+		switch {
+		case i == l:
+			return n.value, true
+		case i < l:
+			n = n.left
+		default:
+			i -= l + 1
+			n = n.right
+		}
+	}
+}
+
+// Size returns the size or cardinality of this set
+func (s *SortedSet) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return sortedSize(s.root)
+}
+
+// String returns a string representation of this set, with elements in ascending order
+func (s *SortedSet) String() string {
+	// Print identifier
+	str := "{ "
+
+	// Check for empty set, print symbol if empty
+	values := s.Enumerate()
+	if len(values) == 0 {
+		return str + "Ø }"
+	}
+
+	// Print all elements
+	for _, v := range values {
+		str = str + formatElement(v, false) + " "
+	}
+
+	return str + "}"
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *SortedSet) Subset(t *SortedSet) bool {
+	return t.Difference(s).Size() == 0
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *SortedSet) SymmetricDifference(t *SortedSet) *SortedSet {
+	// Merge both ordered sets, keeping elements only present in one of the sets
+	return s.merge(t, func(inS bool, inT bool) bool {
+		return inS != inT
+	})
+}
+
+// ToSet copies the elements of the current set into a new, unordered Set
+func (s *SortedSet) ToSet() *Set {
+	return New(s.Enumerate()...)
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *SortedSet) Union(t *SortedSet) *SortedSet {
+	// Merge both ordered sets, keeping all elements
+	return s.merge(t, func(bool, bool) bool {
+		return true
+	})
+}
+
+// merge walks the elements of the current set and the parameter set together in ascending order,
+// as ordered by the current set's comparison function, and returns a new set containing each distinct
+// element for which the function returns true, given whether it is present in either set.  If the
+// parameter set is ordered by a different comparison function, its elements are sorted again first.
+func (s *SortedSet) merge(t *SortedSet, fn func(inS bool, inT bool) bool) *SortedSet {
+	// Enumerate both sets, which are each in ascending order of their own comparison function
+	x := s.Enumerate()
+	y := t.Enumerate()
+
+	// Sort the parameter set again if its order differs from the current set
+	if !s.ordered(y) {
+		y = s.order(y)
+	}
+
+	// Gather kept elements in ascending order
+	values := make([]interface{}, 0, len(x)+len(y))
+	keep := func(v interface{}, inS bool, inT bool) {
+		if fn(inS, inT) {
+			values = append(values, v)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		c := s.compare(x[i], y[j])
+		switch {
+		case c == 0:
+			keep(x[i], true, true)
+			i++
+			j++
+		case c < 0:
+			keep(x[i], true, false)
+			i++
+		default:
+			keep(y[j], false, true)
+			j++
+		}
+	}
+
+	// Drain any remaining elements
+	for ; i < len(x); i++ {
+		keep(x[i], true, false)
+	}
+	for ; j < len(y); j++ {
+		keep(y[j], false, true)
+	}
+
+	// Build the tree of the resulting set directly from the ordered elements
+	return &SortedSet{
+		compare: s.compare,
+		root:    sortedBuild(values),
+	}
+}
+
+// ordered returns whether a slice of values is in strictly ascending order by the set's comparison function
+func (s *SortedSet) ordered(values []interface{}) bool {
+	for i := 1; i < len(values); i++ {
+		if s.compare(values[i-1], values[i]) >= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// order sorts a slice of values by the set's comparison function, keeping only the first of any values
+// which it finds equal
+func (s *SortedSet) order(values []interface{}) []interface{} {
+	sort.SliceStable(values, func(i int, j int) bool {
+		return s.compare(values[i], values[j]) < 0
+	})
+
+	out := values[:0]
+	for _, v := range values {
+		if n := len(out); n == 0 || s.compare(out[n-1], v) != 0 {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// insert adds a value to the subtree rooted at n, returning the new root of the subtree
+func (s *SortedSet) insert(n *sortedNode, value interface{}, added *bool) *sortedNode {
+	// Create a new leaf node
+	if n == nil {
+		*added = true
+		return &sortedNode{
+			value:  value,
+			height: 1,
+			size:   1,
+		}
+	}
+
+	c := s.compare(value, n.value)
+	switch {
+	case c == 0:
+		// Value already exists
+		return n
+	case c < 0:
+		n.left = s.insert(n.left, value, added)
+	default:
+		n.right = s.insert(n.right, value, added)
+	}
+
+	return sortedBalance(n)
+}
+
+// delete removes a value from the subtree rooted at n, returning the new root of the subtree
+func (s *SortedSet) delete(n *sortedNode, value interface{}, removed *bool) *sortedNode {
+	// Value not found
+	if n == nil {
+		return nil
+	}
+
+	c := s.compare(value, n.value)
+	switch {
+	case c < 0:
+		n.left = s.delete(n.left, value, removed)
+	case c > 0:
+		n.right = s.delete(n.right, value, removed)
+	default:
+		*removed = true
+
+		// Nodes with a single child are replaced by that child
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+
+		// Nodes with two children are replaced by their in-order successor
+		m := n.right
+		for m.left != nil {
+			m = m.left
+		}
+		n.value = m.value
+		n.right = s.delete(n.right, m.value, new(bool))
+	}
+
+	return sortedBalance(n)
+}
+
+// sortedWalk performs an in-order walk of the subtree rooted at n
+func sortedWalk(n *sortedNode, fn func(interface{})) {
+	if n == nil {
+		return
+	}
+
+	sortedWalk(n.left, fn)
+	fn(n.value)
+	sortedWalk(n.right, fn)
+}
+
+// sortedHeight returns the height of a subtree, where an empty subtree has height zero
+func sortedHeight(n *sortedNode) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+// sortedSize returns the number of elements in a subtree
+func sortedSize(n *sortedNode) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// sortedUpdate recomputes the height and size of a node from its children
+func sortedUpdate(n *sortedNode) {
+	n.height = 1 + max(sortedHeight(n.left), sortedHeight(n.right))
+	n.size = 1 + sortedSize(n.left) + sortedSize(n.right)
+}
+
+// sortedRotateLeft rotates the subtree rooted at n to the left, returning the new root
+func sortedRotateLeft(n *sortedNode) *sortedNode {
+	r := n.right
+	n.right = r.left
+	r.left = n
+
+	sortedUpdate(n)
+	sortedUpdate(r)
+	return r
+}
+
+// sortedRotateRight rotates the subtree rooted at n to the right, returning the new root
+func sortedRotateRight(n *sortedNode) *sortedNode {
+	l := n.left
+	n.left = l.right
+	l.right = n
+
+	sortedUpdate(n)
+	sortedUpdate(l)
+	return l
+}
+
+// sortedBalance restores the AVL invariant for the subtree rooted at n, returning the new root
+func sortedBalance(n *sortedNode) *sortedNode {
+	sortedUpdate(n)
+
+	switch bf := sortedHeight(n.left) - sortedHeight(n.right); {
+	case bf > 1:
+		// Left heavy, rotate the left child first if it is right heavy
+		if sortedHeight(n.left.left) < sortedHeight(n.left.right) {
+			n.left = sortedRotateLeft(n.left)
+		}
+		return sortedRotateRight(n)
+	case bf < -1:
+		// Right heavy, rotate the right child first if it is left heavy
+		if sortedHeight(n.right.right) < sortedHeight(n.right.left) {
+			n.right = sortedRotateRight(n.right)
+		}
+		return sortedRotateLeft(n)
+	}
+
+	return n
+}
+
+// sortedBuild builds a balanced subtree from a slice of distinct values in ascending order,
+// returning its root
+func sortedBuild(values []interface{}) *sortedNode {
+	if len(values) == 0 {
+		return nil
+	}
+
+	mid := len(values) / 2
+	n := &sortedNode{
+		value: values[mid],
+		left:  sortedBuild(values[:mid]),
+		right: sortedBuild(values[mid+1:]),
+	}
+
+	sortedUpdate(n)
+	return n
+}
@@ -0,0 +1,223 @@
+package set
+
+import (
+	"log"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// compareInts is a comparison function for SortedSets of integers
+func compareInts(a interface{}, b interface{}) int {
+	return a.(int) - b.(int)
+}
+
+// TestSortedEnumerate verifies that the SortedSet.Enumerate() method returns elements in order,
+// while elements are randomly added and removed
+func TestSortedEnumerate(t *testing.T) {
+	log.Println("TestSortedEnumerate()")
+
+	// Create a set, and a map to track expected contents
+	set := NewSorted(compareInts)
+	expected := make(map[int]bool)
+
+	// Randomly add and remove elements
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 2000; i++ {
+		v := r.Intn(200)
+
+		if r.Intn(3) == 0 {
+			if ok := set.Remove(v); ok != expected[v] {
+				t.Fatalf("set.Remove(%d) - unexpected result: %t", v, ok)
+			}
+			delete(expected, v)
+			continue
+		}
+
+		if ok := set.Add(v); ok == expected[v] {
+			t.Fatalf("set.Add(%d) - unexpected result: %t", v, ok)
+		}
+		expected[v] = true
+	}
+
+	// Build the expected ordered slice
+	values := make([]int, 0, len(expected))
+	for v := range expected {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+
+	// Verify order and size
+	out := set.Enumerate()
+	if len(out) != len(values) || set.Size() != len(values) {
+		t.Fatalf("set.Enumerate() - unexpected size: %d != %d", len(out), len(values))
+	}
+	for i := range values {
+		if out[i] != values[i] {
+			t.Fatalf("set.Enumerate() - unexpected element at %d: %v != %d", i, out[i], values[i])
+		}
+	}
+}
+
+// TestSortedQueries verifies that the SortedSet ordered query methods are working properly
+func TestSortedQueries(t *testing.T) {
+	log.Println("TestSortedQueries()")
+
+	// Create a set, add some initial values
+	set := NewSorted(compareInts, 9, 1, 5, 3, 7)
+
+	// Verify minimum and maximum
+	if min, ok := set.Min(); !ok || min != 1 {
+		t.Fatalf("set.Min() - unexpected result: %v", min)
+	}
+	if max, ok := set.Max(); !ok || max != 9 {
+		t.Fatalf("set.Max() - unexpected result: %v", max)
+	}
+
+	// Create a table of tests and expected results of floor and ceiling queries
+	var tests = []struct {
+		value   int
+		floor   interface{}
+		ceiling interface{}
+		rank    int
+	}{
+		{0, nil, 1, 0},
+		{1, 1, 1, 0},
+		{4, 3, 5, 2},
+		{9, 9, 9, 4},
+		{10, 9, nil, 5},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if floor, _ := set.Floor(test.value); floor != test.floor {
+			t.Fatalf("set.Floor(%d) - unexpected result: %v", test.value, floor)
+		}
+		if ceiling, _ := set.Ceiling(test.value); ceiling != test.ceiling {
+			t.Fatalf("set.Ceiling(%d) - unexpected result: %v", test.value, ceiling)
+		}
+		if rank := set.Rank(test.value); rank != test.rank {
+			t.Fatalf("set.Rank(%d) - unexpected result: %d", test.value, rank)
+		}
+
+		log.Println(set, "⌊", test.value, "⌋ =", test.floor, "⌈", test.value, "⌉ =", test.ceiling)
+	}
+
+	// Verify selection by position
+	for i, v := range set.Enumerate() {
+		if s, ok := set.Select(i); !ok || s != v {
+			t.Fatalf("set.Select(%d) - unexpected result: %v", i, s)
+		}
+	}
+	if _, ok := set.Select(5); ok {
+		t.Fatalf("set.Select(5) - expected no result")
+	}
+
+	// Verify ranges
+	if r := NewSorted(compareInts, set.Range(2, 7)...); !r.Equal(NewSorted(compareInts, 3, 5, 7)) {
+		t.Fatalf("set.Range(2, 7) - unexpected result: %s", r)
+	}
+}
+
+// TestSortedAlgebra verifies that the SortedSet set algebra methods are working properly
+func TestSortedAlgebra(t *testing.T) {
+	log.Println("TestSortedAlgebra()")
+
+	// Create a set, add some initial values
+	set := NewSorted(compareInts, 1, 3, 5)
+	other := NewSorted(compareInts, 1, 2, 6)
+
+	// Create a table of tests and expected results of set algebra
+	var tests = []struct {
+		name   string
+		result *SortedSet
+		target *SortedSet
+	}{
+		{"Union", set.Union(other), NewSorted(compareInts, 1, 2, 3, 5, 6)},
+		{"Intersection", set.Intersection(other), NewSorted(compareInts, 1)},
+		{"Difference", set.Difference(other), NewSorted(compareInts, 3, 5)},
+		{"SymmetricDifference", set.SymmetricDifference(other), NewSorted(compareInts, 2, 3, 5, 6)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+
+		log.Println(test.name, "(", set, ",", other, ") =", test.result)
+	}
+
+	// Verify subsets and conversion
+	if !set.Subset(NewSorted(compareInts, 1, 5)) || set.Subset(other) {
+		t.Fatalf("set.Subset() - unexpected result")
+	}
+	if !set.ToSet().Equal(New(1, 3, 5)) {
+		t.Fatalf("set.ToSet() - unexpected result: %s", set.ToSet())
+	}
+}
+
+// TestSortedAlgebraOrder verifies that the SortedSet set algebra methods return well formed sets, including
+// when the parameter set is ordered by a different comparison function
+func TestSortedAlgebraOrder(t *testing.T) {
+	log.Println("TestSortedAlgebraOrder()")
+
+	// Create a set of even numbers, and a set of multiples of three in descending order
+	set := NewSorted(compareInts)
+	other := NewSorted(func(a interface{}, b interface{}) int { return b.(int) - a.(int) })
+	for i := 0; i < 300; i++ {
+		set.Add(i * 2)
+		other.Add(i * 3)
+	}
+
+	// Create a table of tests and expected sizes
+	var tests = []struct {
+		name   string
+		result *SortedSet
+		size   int
+	}{
+		{"Union", set.Union(other), 500},
+		{"Intersection", set.Intersection(other), 100},
+		{"Difference", set.Difference(other), 200},
+		{"SymmetricDifference", set.SymmetricDifference(other), 400},
+	}
+
+	// Iterate test table, checking that results are ordered and ranked by the current set's function
+	for _, test := range tests {
+		out := test.result.Enumerate()
+		if len(out) != test.size || test.result.Size() != test.size {
+			t.Fatalf("set.%s() - unexpected size: %d != %d", test.name, len(out), test.size)
+		}
+		for i, v := range out {
+			if i > 0 && out[i-1].(int) >= v.(int) {
+				t.Fatalf("set.%s() - elements out of order: %v", test.name, out)
+			}
+			if e, ok := test.result.Select(i); !ok || e != v || test.result.Rank(v) != i {
+				t.Fatalf("set.%s() - unexpected rank of %v: %d", test.name, v, test.result.Rank(v))
+			}
+		}
+
+		// Results can be modified further
+		if !test.result.Add(-1) || !test.result.Remove(-1) || test.result.Size() != test.size {
+			t.Fatalf("set.%s() - result cannot be modified", test.name)
+		}
+	}
+}
+
+// TestSortedString verifies that the SortedSet.String() method prints elements in ascending order
+func TestSortedString(t *testing.T) {
+	log.Println("TestSortedString()")
+
+	if s := NewSorted(compareInts).String(); s != "{ Ø }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+	if s := NewSorted(compareInts, 3, 1, 2).String(); s != "{ 1 2 3 }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+
+	// Pairs are printed like in other sets
+	byX := func(a interface{}, b interface{}) int { return a.(Pair).X.(int) - b.(Pair).X.(int) }
+	if s := NewSorted(byX, Pair{2, "b"}, Pair{1, "a"}).String(); s != "{ (1, a) (2, b) }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+}
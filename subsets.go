@@ -0,0 +1,207 @@
+package set
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+)
+
+// Subsets returns an iterator over all possible subsets of the set, for use with a range loop.  Unlike
+// PowerSet, subsets are generated on demand, so memory use is proportional to the size of the set,
+// rather than the size of its power set.
+//
+// Subsets are produced in Gray code order, beginning with the empty set, so that each subset differs
+// from the previous subset by exactly one element.  To avoid an allocation per subset, the same slice
+// is reused for every subset produced, and the order of elements within it is unspecified.  Callers
+// which retain a subset after the loop body must copy it.
+//
+// A set of n elements has 2^n subsets, so iteration over sets with more than a few dozen elements
+// will not complete in practice, and should be stopped early.  Use PowerSetSize to check the number
+// of subsets first.  The elements of the set are gathered when iteration begins.
+func (s *Set) Subsets() iter.Seq[[]interface{}] {
+	return subsetsOf(s.Enumerate)
+}
+
+// subsetsOf returns an iterator over all possible subsets of the elements returned by a function, which
+// is called when iteration begins
+func subsetsOf(enumerate func() []interface{}) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		// Gather the elements of the set
+		elements := enumerate()
+		n := len(elements)
+
+		// Track the position of each element within the subset, or -1 if not present, and the element
+		// at each position within the subset
+		pos := make([]int, n)
+		for i := range pos {
+			pos[i] = -1
+		}
+		members := make([]int, 0, n)
+
+		// Begin with the empty set
+		subset := make([]interface{}, 0, n)
+		if !yield(subset) {
+			return
+		}
+
+		// Count the subsets using as many words as needed to hold 2^n, so that sets of any size are
+		// supported
+		counter := make([]uint64, n/64+1)
+		for {
+			// Each step of a Gray code flips the bit at the position of the lowest set bit of the
+			// counter, and the counter reaching 2^n means iteration is complete
+			j := incrementCounter(counter)
+			if j == n {
+				return
+			}
+
+			if p := pos[j]; p >= 0 {
+				// Remove the element by moving the last element into its place
+				last := len(subset) - 1
+				members[p] = members[last]
+				subset[p] = subset[last]
+				pos[members[p]] = p
+
+				members = members[:last]
+				subset[last] = nil
+				subset = subset[:last]
+				pos[j] = -1
+			} else {
+				// Add the element to the end of the subset
+				pos[j] = len(subset)
+				members = append(members, j)
+				subset = append(subset, elements[j])
+			}
+
+			if !yield(subset) {
+				return
+			}
+		}
+	}
+}
+
+// incrementCounter adds one to a multi-word counter, stored with its least significant word first,
+// returning the position of the lowest set bit of the result.  The counter must not overflow.
+func incrementCounter(counter []uint64) int {
+	// Carry into the next word on overflow
+	w := 0
+	for counter[w]++; counter[w] == 0; counter[w]++ {
+		w++
+	}
+
+	return w*64 + bits.TrailingZeros64(counter[w])
+}
+
+// Combinations returns an iterator over all subsets of the set which contain exactly k elements, for use
+// with a range loop.  Subsets are generated on demand, so memory use is proportional to the size of the
+// set, rather than the number of combinations.
+//
+// To avoid an allocation per subset, the same slice is reused for every subset produced.  Callers which
+// retain a subset after the loop body must copy it.  Use CombinationsSize to check the number of subsets
+// first.  The elements of the set are gathered when iteration begins.
+func (s *Set) Combinations(k int) iter.Seq[[]interface{}] {
+	return combinationsOf(s.Enumerate, k)
+}
+
+// combinationsOf returns an iterator over all subsets of exactly k of the elements returned by a
+// function, which is called when iteration begins
+func combinationsOf(enumerate func() []interface{}, k int) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		// Gather the elements of the set
+		elements := enumerate()
+		n := len(elements)
+
+		// No combinations of this size exist
+		if k < 0 || k > n {
+			return
+		}
+
+		// Begin with the first k elements
+		indices := make([]int, k)
+		subset := make([]interface{}, k)
+		for i := range indices {
+			indices[i] = i
+			subset[i] = elements[i]
+		}
+
+		for {
+			if !yield(subset) {
+				return
+			}
+
+			// Find the rightmost index which can still be advanced
+			i := k - 1
+			for i >= 0 && indices[i] == n-k+i {
+				i--
+			}
+
+			// All combinations have been produced
+			if i < 0 {
+				return
+			}
+
+			// Advance the index, and reset all indices after it
+			indices[i]++
+			subset[i] = elements[indices[i]]
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[j-1] + 1
+				subset[j] = elements[indices[j]]
+			}
+		}
+	}
+}
+
+// CombinationsSize returns the number of subsets of the set which contain exactly k elements, without
+// generating any subsets.  If the number does not fit in an int, CombinationsSize returns false.
+func (s *Set) CombinationsSize(k int) (int, bool) {
+	return combinationsSize(s.Size(), k)
+}
+
+// combinationsSize returns the number of subsets of exactly k of n elements, and whether or not the
+// number fits in an int
+func combinationsSize(n int, k int) (int, bool) {
+	// No combinations of this size exist
+	if k < 0 || k > n {
+		return 0, true
+	}
+
+	// C(n, k) == C(n, n-k), so use the smaller of the two
+	if n-k < k {
+		k = n - k
+	}
+
+	// Compute C(n, i+1) = C(n, i) * (n-i) / (i+1), which is always an exact division
+	c := uint64(1)
+	for i := 0; i < k; i++ {
+		hi, lo := bits.Mul64(c, uint64(n-i))
+
+		// The quotient does not fit in 64 bits
+		if hi >= uint64(i+1) {
+			return 0, false
+		}
+
+		c, _ = bits.Div64(hi, lo, uint64(i+1))
+	}
+
+	if c > math.MaxInt {
+		return 0, false
+	}
+
+	return int(c), true
+}
+
+// PowerSetSize returns the number of subsets in the power set of the set, without generating any
+// subsets.  If the number does not fit in an int, PowerSetSize returns false.
+func (s *Set) PowerSetSize() (int, bool) {
+	return powerSetSize(s.Size())
+}
+
+// powerSetSize returns the number of subsets of n elements, and whether or not the number fits in an int
+func powerSetSize(n int) (int, bool) {
+	// A set of n elements has 2^n subsets
+	if n >= bits.UintSize-1 {
+		return 0, false
+	}
+
+	return 1 << uint(n), true
+}
@@ -0,0 +1,173 @@
+package set
+
+import (
+	"log"
+	"math"
+	"testing"
+)
+
+// TestSubsets verifies that the set.Subsets() method is working properly
+func TestSubsets(t *testing.T) {
+	log.Println("TestSubsets()")
+
+	// Create a table of sets to generate subsets of
+	var tests = []*Set{
+		New(),
+		New(1),
+		New(1, 3, 5),
+		New(1, 2, 3, 4, 5, 6),
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		size, ok := test.PowerSetSize()
+		if !ok {
+			t.Fatalf("set.PowerSetSize() - unexpected overflow")
+		}
+
+		// Verify each subset is distinct, contained in the set, and differs from the previous
+		// subset by one element
+		seen := make(map[string]bool)
+		var previous *Set
+		for subset := range test.Subsets() {
+			current := New(subset...)
+			if current.Size() != len(subset) || !test.Subset(current) {
+				t.Fatalf("set.Subsets() - invalid subset: %v", subset)
+			}
+
+			key := NewSorted(compareInts, subset...).String()
+			if seen[key] {
+				t.Fatalf("set.Subsets() - duplicate subset: %s", key)
+			}
+			seen[key] = true
+
+			if previous != nil && previous.SymmetricDifference(current).Size() != 1 {
+				t.Fatalf("set.Subsets() - not in Gray code order: %s -> %s", previous, current)
+			}
+			previous = current
+		}
+
+		if len(seen) != size {
+			t.Fatalf("set.Subsets() - unexpected number of subsets: %d != %d", len(seen), size)
+		}
+
+		log.Println("P(", test, ") ->", len(seen))
+	}
+}
+
+// TestCombinations verifies that the set.Combinations() and set.CombinationsSize() methods are
+// working properly
+func TestCombinations(t *testing.T) {
+	log.Println("TestCombinations()")
+
+	// Create a set, add some initial values
+	set := New(1, 2, 3, 4, 5)
+
+	// Create a table of tests and expected number of combinations
+	var tests = []struct {
+		k     int
+		count int
+	}{
+		{-1, 0},
+		{0, 1},
+		{1, 5},
+		{2, 10},
+		{3, 10},
+		{5, 1},
+		{6, 0},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if size, ok := set.CombinationsSize(test.k); !ok || size != test.count {
+			t.Fatalf("set.CombinationsSize(%d) - unexpected result: %d, %t", test.k, size, ok)
+		}
+
+		// Verify each combination is distinct, of the right size, and contained in the set
+		seen := make(map[string]bool)
+		for subset := range set.Combinations(test.k) {
+			current := NewSorted(compareInts, subset...)
+			if current.Size() != test.k || !set.Subset(current.ToSet()) {
+				t.Fatalf("set.Combinations(%d) - invalid subset: %v", test.k, subset)
+			}
+
+			if seen[current.String()] {
+				t.Fatalf("set.Combinations(%d) - duplicate subset: %s", test.k, current)
+			}
+			seen[current.String()] = true
+		}
+
+		if len(seen) != test.count {
+			t.Fatalf("set.Combinations(%d) - unexpected number of subsets: %d", test.k, len(seen))
+		}
+
+		log.Println("C(", set, ",", test.k, ") ->", len(seen))
+	}
+}
+
+// TestSubsetsOverflow verifies that subset counts detect overflow, and that iteration over large
+// sets may be stopped early
+func TestSubsetsOverflow(t *testing.T) {
+	log.Println("TestSubsetsOverflow()")
+
+	// Create a set which is far too large to enumerate all subsets of
+	set := New()
+	for i := 0; i < 100; i++ {
+		set.Add(i)
+	}
+
+	if _, ok := set.PowerSetSize(); ok {
+		t.Fatalf("set.PowerSetSize() - expected overflow")
+	}
+	if _, ok := set.CombinationsSize(50); ok {
+		t.Fatalf("set.CombinationsSize(50) - expected overflow")
+	}
+	if size, ok := set.CombinationsSize(3); !ok || size != 161700 {
+		t.Fatalf("set.CombinationsSize(3) - unexpected result: %d, %t", size, ok)
+	}
+
+	// Stop iteration early
+	count := 0
+	for range set.Subsets() {
+		count++
+		if count == 1000 {
+			break
+		}
+	}
+	if count != 1000 {
+		t.Fatalf("set.Subsets() - unexpected number of subsets after break: %d", count)
+	}
+}
+
+// TestIncrementCounter verifies that the counter used by set.Subsets() carries between words, so that
+// sets of more than 64 elements produce every subset
+func TestIncrementCounter(t *testing.T) {
+	log.Println("TestIncrementCounter()")
+
+	// Create a table of counters and expected results
+	var tests = []struct {
+		counter []uint64
+		bit     int
+		target  []uint64
+	}{
+		{[]uint64{0}, 0, []uint64{1}},
+		{[]uint64{1}, 1, []uint64{2}},
+		{[]uint64{1<<63 - 1, 0}, 63, []uint64{1 << 63, 0}},
+		{[]uint64{math.MaxUint64, 0}, 64, []uint64{0, 1}},
+		{[]uint64{math.MaxUint64, 1, 0}, 65, []uint64{0, 2, 0}},
+		{[]uint64{math.MaxUint64, math.MaxUint64, 0}, 128, []uint64{0, 0, 1}},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if bit := incrementCounter(test.counter); bit != test.bit {
+			t.Fatalf("set.incrementCounter() - unexpected bit: %d != %d", bit, test.bit)
+		}
+
+		for i := range test.counter {
+			if test.counter[i] != test.target[i] {
+				t.Fatalf("set.incrementCounter() - unexpected result: %v != %v", test.counter, test.target)
+			}
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Parse reads a set from its textual representation, as produced by String or MarshalText.  The
+// syntax is as follows:
+//
+//	{ Ø }              the empty set, which may also be written as { }
+//	{ 1 2 3 }          elements separated by whitespace
+//	(1, "a")           a Pair of two elements
+//	{ { 1 } { 2 } }    nested sets
+//
+// Literals are typed as follows: integers such as 1 or -2 become int, numbers with a decimal point
+// or exponent, NaN, Inf, +Inf and -Inf become float64, "quoted" strings become string, true and false
+// become bool, and nil becomes nil.  Any other bare word, such as the unquoted strings printed by
+// String, also becomes a string.  Sets and pairs may be nested at most 10000 deep.
+func Parse(str string) (*Set, error) {
+	p := &textParser{str: str}
+
+	// Parse a single set, which must consume the entire input
+	p.skipSpace()
+	s, err := p.parseSet()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.str) {
+		return nil, p.errorf("unexpected trailing text")
+	}
+
+	return s, nil
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the set using the syntax read by Parse.
+// Elements are written in canonical sorted order, and strings are always quoted, so that the set
+// can be read back exactly.  Only elements of type nil, bool, int, float64, string, Pair, *Set and
+// FrozenSet can be encoded, and any other type returns an error.  A FrozenSet is encoded as a nested
+// set, and so is read back as a *Set.
+func (s *Set) MarshalText() ([]byte, error) {
+	return appendTextSet(nil, s)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding a set using Parse, and replacing the
+// contents of the current set
+func (s *Set) UnmarshalText(b []byte) error {
+	t, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+
+	// Lock set for write, and replace its contents
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replace(t.m)
+	return nil
+}
+
+// appendTextSet appends the sorted elements of a set to a buffer
+func appendTextSet(b []byte, s *Set) ([]byte, error) {
+	return appendTextValues(b, s.Enumerate())
+}
+
+// appendTextValues sorts a slice of elements, and appends them to a buffer as a set
+func appendTextValues(b []byte, values []interface{}) ([]byte, error) {
+	SortElements(values)
+
+	// Check for empty set, print symbol if empty
+	b = append(b, "{ "...)
+	if len(values) == 0 {
+		return append(b, "Ø }"...), nil
+	}
+
+	// Print all elements
+	for _, v := range values {
+		var err error
+		if b, err = appendTextElement(b, v); err != nil {
+			return nil, err
+		}
+
+		b = append(b, ' ')
+	}
+
+	return append(b, '}'), nil
+}
+
+// appendTextElement appends a single element to a buffer
+func appendTextElement(b []byte, v interface{}) ([]byte, error) {
+	switch e := v.(type) {
+	case nil:
+		return append(b, "nil"...), nil
+	case bool:
+		return strconv.AppendBool(b, e), nil
+	case int:
+		return strconv.AppendInt(b, int64(e), 10), nil
+	case float64:
+		// Ensure that whole numbers are read back as floats
+		str := strconv.FormatFloat(e, 'g', -1, 64)
+		if math.IsInf(e, 1) {
+			str = "+Inf"
+		} else if !strings.ContainsAny(str, ".eIN") {
+			str += ".0"
+		}
+		return append(b, str...), nil
+	case string:
+		return strconv.AppendQuote(b, e), nil
+	case Pair:
+		b, err := appendTextElement(append(b, '('), e.X)
+		if err != nil {
+			return nil, err
+		}
+		b, err = appendTextElement(append(b, ", "...), e.Y)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, ')'), nil
+	case *Set:
+		return appendTextSet(b, e)
+	case FrozenSet:
+		return appendTextSet(b, e.Thaw())
+	default:
+		return nil, fmt.Errorf("set: cannot text encode element %v of type %T", v, v)
+	}
+}
+
+// textParser reads a set from its textual representation
+type textParser struct {
+	// Input being parsed
+	str string
+	// Current position within the input
+	pos int
+	// Number of sets and pairs currently being parsed
+	depth int
+}
+
+// errorf returns an error describing a problem at the current position of the input
+func (p *textParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("set: parse error at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// skipSpace advances past any whitespace
+func (p *textParser) skipSpace() {
+	for p.pos < len(p.str) {
+		r, size := utf8.DecodeRuneInString(p.str[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+
+		p.pos += size
+	}
+}
+
+// consume advances past a prefix if it is next in the input, returning whether or not it was found
+func (p *textParser) consume(prefix string) bool {
+	if !strings.HasPrefix(p.str[p.pos:], prefix) {
+		return false
+	}
+
+	p.pos += len(prefix)
+	return true
+}
+
+// nest records the start of a nested set or pair, returning an error if the nesting is too deep, and
+// a function which records its end
+func (p *textParser) nest() (func(), error) {
+	if p.depth >= maxNestingDepth {
+		return nil, p.errorf("sets and pairs nested too deeply")
+	}
+
+	p.depth++
+	return func() {
+		p.depth--
+	}, nil
+}
+
+// parseSet reads a set, beginning with its opening brace
+func (p *textParser) parseSet() (*Set, error) {
+	if !p.consume("{") {
+		return nil, p.errorf("expected '{'")
+	}
+
+	unnest, err := p.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer unnest()
+
+	s := New()
+	p.skipSpace()
+
+	// Check for empty set symbol
+	if p.consume("Ø") {
+		p.skipSpace()
+		if !p.consume("}") {
+			return nil, p.errorf("expected '}' after 'Ø'")
+		}
+
+		return s, nil
+	}
+
+	// Read elements until the closing brace
+	for !p.consume("}") {
+		if p.pos == len(p.str) {
+			return nil, p.errorf("expected '}'")
+		}
+
+		v, err := p.parseElement()
+		if err != nil {
+			return nil, err
+		}
+
+		s.m[v] = struct{}{}
+		p.skipSpace()
+	}
+
+	return s, nil
+}
+
+// parseElement reads a single element
+func (p *textParser) parseElement() (interface{}, error) {
+	if p.pos == len(p.str) {
+		return nil, p.errorf("expected element")
+	}
+
+	switch p.str[p.pos] {
+	case '{':
+		return p.parseSet()
+	case '(':
+		return p.parsePair()
+	case '"', '`':
+		return p.parseString()
+	default:
+		return p.parseWord()
+	}
+}
+
+// parsePair reads a pair, beginning with its opening parenthesis
+func (p *textParser) parsePair() (interface{}, error) {
+	p.consume("(")
+	p.skipSpace()
+
+	unnest, err := p.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer unnest()
+
+	x, err := p.parseElement()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if !p.consume(",") {
+		return nil, p.errorf("expected ',' in pair")
+	}
+	p.skipSpace()
+
+	y, err := p.parseElement()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if !p.consume(")") {
+		return nil, p.errorf("expected ')' in pair")
+	}
+
+	return Pair{X: x, Y: y}, nil
+}
+
+// parseString reads a quoted string, using Go syntax
+func (p *textParser) parseString() (interface{}, error) {
+	prefix, err := strconv.QuotedPrefix(p.str[p.pos:])
+	if err != nil {
+		return nil, p.errorf("invalid quoted string")
+	}
+
+	str, err := strconv.Unquote(prefix)
+	if err != nil {
+		return nil, p.errorf("invalid quoted string")
+	}
+
+	p.pos += len(prefix)
+	return str, nil
+}
+
+// parseWord reads a bare word, and determines its type
+func (p *textParser) parseWord() (interface{}, error) {
+	// Read until whitespace or punctuation
+	start := p.pos
+	for p.pos < len(p.str) {
+		r, size := utf8.DecodeRuneInString(p.str[p.pos:])
+		if unicode.IsSpace(r) || strings.ContainsRune("{}(),\"`", r) {
+			break
+		}
+
+		p.pos += size
+	}
+
+	word := p.str[start:p.pos]
+	if word == "" {
+		return nil, p.errorf("unexpected %q", p.str[p.pos])
+	}
+
+	switch word {
+	case "nil", "<nil>":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "NaN", "Inf", "+Inf", "-Inf":
+		f, _ := strconv.ParseFloat(word, 64)
+		return f, nil
+	}
+
+	// Check for numbers, which always begin with a digit or sign
+	if c := word[0]; c != '-' && c != '+' && (c < '0' || c > '9') {
+		return word, nil
+	}
+
+	if i, err := strconv.ParseInt(word, 10, 0); err == nil {
+		return int(i), nil
+	} else if errors.Is(err, strconv.ErrRange) {
+		return nil, p.errorf("integer %s out of range", word)
+	}
+
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, nil
+	} else if errors.Is(err, strconv.ErrRange) {
+		return nil, p.errorf("float %s out of range", word)
+	}
+
+	// Not a number, so treat as a string
+	return word, nil
+}
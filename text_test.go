@@ -0,0 +1,171 @@
+package set
+
+import (
+	"encoding"
+	"flag"
+	"log"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Ensure that Set can be used wherever text encoding is supported
+var (
+	_ encoding.TextMarshaler   = New()
+	_ encoding.TextUnmarshaler = New()
+)
+
+// TestParse verifies that the set.Parse() function is working properly
+func TestParse(t *testing.T) {
+	log.Println("TestParse()")
+
+	// Create a table of tests and expected results of parsing
+	var tests = []struct {
+		source string
+		result *Set
+	}{
+		// Empty set
+		{"{ Ø }", New()},
+		{"{}", New()},
+		{"  {   }  ", New()},
+		// Integers
+		{"{ 1 2 -3 +4 }", New(1, 2, -3, 4)},
+		// Floats
+		{"{ 1.5 -2.0 1e3 +Inf -Inf }", New(1.5, -2.0, 1000.0, math.Inf(1), math.Inf(-1))},
+		// Quoted strings
+		{`{ "a b" "}" "\n" ` + "`x`" + ` }`, New("a b", "}", "\n", "x")},
+		// Bare strings, as printed by String
+		{"{ a b-c 1x }", New("a", "b-c", "1x")},
+		// Booleans and nil
+		{"{ true false nil }", New(true, false, nil)},
+		{"{ <nil> }", New(nil)},
+		// Duplicates
+		{"{ 1 1 1 }", New(1)},
+		// Pairs
+		{`{ (1, 3) (1,"a") ( 2 , (3, 4) ) }`, New(Pair{1, 3}, Pair{1, "a"}, Pair{2, Pair{3, 4}})},
+		// Nested sets
+		{"{ { 1 2 } { Ø } 3 }", New(New(1, 2), New(), 3)},
+		// Sets inside pairs
+		{"{ ({ 1 }, 2) }", New(Pair{New(1), 2})},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		s, err := Parse(test.source)
+		if err != nil {
+			t.Fatalf("set.Parse(%q) - unexpected error: %v", test.source, err)
+		}
+		if !textEqual(s, test.result) {
+			t.Fatalf("set.Parse(%q) - unexpected result: %s != %s", test.source, s, test.result)
+		}
+	}
+}
+
+// TestParseErrors verifies that the set.Parse() function rejects malformed input
+func TestParseErrors(t *testing.T) {
+	log.Println("TestParseErrors()")
+
+	// Create a table of malformed input
+	var tests = []string{
+		"",
+		"1 2",
+		"{ 1 2",
+		"{ 1 } 2",
+		"{ Ø 1 }",
+		"{ (1 2) }",
+		"{ (1, 2 }",
+		"{ (1, ) }",
+		`{ "a }`,
+		"{ ) }",
+		"{ 99999999999999999999 }",
+		"{ 1e999 }",
+		// Nesting beyond the limit
+		strings.Repeat("{", 1000000),
+		strings.Repeat("{ (", 1000000),
+		"{ " + strings.Repeat("(", maxNestingDepth) + "1" + strings.Repeat(", 1)", maxNestingDepth) + " }",
+	}
+
+	// Iterate test table, checking for errors
+	for _, test := range tests {
+		if s, err := Parse(test); err == nil {
+			t.Fatalf("set.Parse(%.20q) - expected error, got: %s", test, s)
+		}
+	}
+
+	// Nesting within the limit is accepted
+	deep := strings.Repeat("{ ", maxNestingDepth) + strings.Repeat("} ", maxNestingDepth)
+	if _, err := Parse(deep); err != nil {
+		t.Fatalf("set.Parse() - unexpected error for nesting within limit: %v", err)
+	}
+}
+
+// TestMarshalText verifies that the set.MarshalText() and set.UnmarshalText() methods are working properly
+func TestMarshalText(t *testing.T) {
+	log.Println("TestMarshalText()")
+
+	// Create a table of tests and expected results of text encoding
+	var tests = []struct {
+		source *Set
+		result string
+	}{
+		// Empty set
+		{New(), "{ Ø }"},
+		// Numbers, in sorted order
+		{New(3, 1, 2), "{ 1 2 3 }"},
+		// Floats are distinguished from integers
+		{New(2.0, 1.5, math.Inf(1), math.Inf(-1)), "{ -Inf 1.5 2.0 +Inf }"},
+		// Strings are always quoted
+		{New("b", "a c", "1"), `{ "1" "a c" "b" }`},
+		// Mixed types
+		{New(nil, true, "x", 1), `{ nil true 1 "x" }`},
+		// Pairs
+		{New(Pair{1, "a"}), `{ (1, "a") }`},
+		// Nested sets
+		{New(New(2, 1), New()), "{ { 1 2 } { Ø } }"},
+	}
+
+	// Iterate test table, checking results and round trips
+	for _, test := range tests {
+		b, err := test.source.MarshalText()
+		if err != nil {
+			t.Fatalf("set.MarshalText() - unexpected error: %v", err)
+		}
+		if string(b) != test.result {
+			t.Fatalf("set.MarshalText() - unexpected result: %s != %s", b, test.result)
+		}
+
+		s := New("stale")
+		if err := s.UnmarshalText(b); err != nil {
+			t.Fatalf("set.UnmarshalText(%q) - unexpected error: %v", b, err)
+		}
+		if !textEqual(s, test.source) {
+			t.Fatalf("set.UnmarshalText() - sets not equal: %s != %s", s, test.source)
+		}
+	}
+
+	// Unsupported types cannot be encoded
+	if _, err := New(int8(1)).MarshalText(); err == nil {
+		t.Fatalf("set.MarshalText() - expected error for unsupported type")
+	}
+}
+
+// TestTextFlag verifies that a set can be used as a command line flag value
+func TestTextFlag(t *testing.T) {
+	log.Println("TestTextFlag()")
+
+	s := New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.TextVar(s, "set", New(), "set of values")
+
+	if err := fs.Parse([]string{"-set", `{ 1 "a" (2, 3) }`}); err != nil {
+		t.Fatalf("flag.Parse() - unexpected error: %v", err)
+	}
+	if !textEqual(s, New(1, "a", Pair{2, 3})) {
+		t.Fatalf("flag.Parse() - unexpected result: %s", s)
+	}
+}
+
+// textEqual compares two sets by their text encoding, so that nested sets are compared by value
+func textEqual(s *Set, t *Set) bool {
+	return s.String() == t.String() && s.Size() == t.Size()
+}
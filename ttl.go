@@ -0,0 +1,317 @@
+package set
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// TTLOptions configures the expiry behavior of a TTLSet
+type TTLOptions struct {
+	// TTL is the lifetime of elements added using Add.  If zero, those elements never expire.
+	TTL time.Duration
+	// Now returns the current time, and is used to determine when elements expire.  If nil, time.Now
+	// is used.  Tests may provide a fake clock to control expiry without sleeping.
+	Now func() time.Time
+	// OnExpire, if not nil, is called once for each element which expires, in order of expiry.  It is
+	// called without the set locked, so it may safely access the set.  It is not called for elements
+	// which are removed using Remove.
+	OnExpire func(value interface{})
+}
+
+// TTLSet represents an unordered collection of unique values, each of which may have a limited
+// lifetime.  Once an element's lifetime has passed, it is no longer a member of the set, and is
+// excluded from Has, Size, Enumerate and all other methods.
+//
+// Expired elements are removed, and OnExpire callbacks are made, whenever the set is next accessed,
+// or when Expire is called.  Use StartExpiry to expire elements periodically, so that callbacks are
+// made promptly even when the set is idle.  A TTLSet is safe for concurrent use.
+type TTLSet struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.Mutex
+	// Expiry options
+	opts TTLOptions
+	// Entry for each element, holding its expiry time
+	m map[interface{}]*ttlEntry
+	// Min-heap of entries which expire, ordered by expiry time
+	expiries ttlHeap
+}
+
+// ttlEntry holds the expiry time of a single element in a TTLSet
+type ttlEntry struct {
+	// Element of the set
+	value interface{}
+	// Time at which the element expires, or the zero time if it never expires
+	expires time.Time
+	// Index of the entry within the expiry heap, or -1 if it never expires
+	index int
+}
+
+// NewTTL creates a new TTLSet using the specified options, optionally adding initial elements to the
+// set with the default lifetime
+func NewTTL(opts TTLOptions, values ...interface{}) *TTLSet {
+	// Use the system clock by default
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	// Initialize set
+	s := TTLSet{
+		opts: opts,
+		m:    make(map[interface{}]*ttlEntry, len(values)),
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// Add inserts a new element into the set with the default lifetime, returning true if the element
+// was newly added, or false if it already existed.  If the element already existed, its lifetime is
+// restarted.
+func (s *TTLSet) Add(value interface{}) bool {
+	return s.AddTTL(value, s.opts.TTL)
+}
+
+// AddTTL inserts a new element into the set with the specified lifetime, returning true if the element
+// was newly added, or false if it already existed.  If the element already existed, its lifetime is
+// replaced.  If ttl is zero or negative, the element never expires.
+func (s *TTLSet) AddTTL(value interface{}, ttl time.Duration) bool {
+	// Lock set for write, and remove expired elements
+	s.mutex.Lock()
+	expired := s.expire()
+
+	// Determine expiry time
+	var expires time.Time
+	if ttl > 0 {
+		expires = s.opts.Now().Add(ttl)
+	}
+
+	// Check existence, add or update value in set
+	e, ok := s.m[value]
+	if !ok {
+		e = &ttlEntry{
+			value: value,
+			index: -1,
+		}
+		s.m[value] = e
+	}
+	s.schedule(e, expires)
+
+	s.mutex.Unlock()
+	s.notify(expired)
+
+	return !ok
+}
+
+// Enumerate returns an unordered slice of all unexpired elements in the set
+func (s *TTLSet) Enumerate() []interface{} {
+	// Lock set for write, and remove expired elements
+	s.mutex.Lock()
+	expired := s.expire()
+
+	// Gather all values into a slice
+	values := make([]interface{}, 0, len(s.m))
+	for k := range s.m {
+		values = append(values, k)
+	}
+
+	s.mutex.Unlock()
+	s.notify(expired)
+
+	return values
+}
+
+// Expire removes all elements whose lifetime has passed, making OnExpire callbacks for each of them,
+// and returns the number of elements which were removed
+func (s *TTLSet) Expire() int {
+	// Lock set for write, and remove expired elements
+	s.mutex.Lock()
+	expired := s.expire()
+	s.mutex.Unlock()
+
+	s.notify(expired)
+	return len(expired)
+}
+
+// Expiry returns the time at which an element expires.  If the element never expires, the zero time
+// is returned.  If the element is not a member of the set, Expiry returns false.
+func (s *TTLSet) Expiry(value interface{}) (time.Time, bool) {
+	// Lock set for write, and remove expired elements
+	s.mutex.Lock()
+	expired := s.expire()
+
+	var expires time.Time
+	e, ok := s.m[value]
+	if ok {
+		expires = e.expires
+	}
+
+	s.mutex.Unlock()
+	s.notify(expired)
+
+	return expires, ok
+}
+
+// Has checks for membership of an unexpired element in the set
+func (s *TTLSet) Has(value interface{}) bool {
+	_, ok := s.Expiry(value)
+	return ok
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it
+// did not exist or had already expired.  No OnExpire callback is made for the removed element.
+func (s *TTLSet) Remove(value interface{}) bool {
+	// Lock set for write, and remove expired elements
+	s.mutex.Lock()
+	expired := s.expire()
+
+	// Check existence, remove value from set
+	e, ok := s.m[value]
+	if ok {
+		s.schedule(e, time.Time{})
+		delete(s.m, value)
+	}
+
+	s.mutex.Unlock()
+	s.notify(expired)
+
+	return ok
+}
+
+// Size returns the number of unexpired elements in the set
+func (s *TTLSet) Size() int {
+	// Lock set for write, and remove expired elements
+	s.mutex.Lock()
+	expired := s.expire()
+	size := len(s.m)
+	s.mutex.Unlock()
+
+	s.notify(expired)
+	return size
+}
+
+// StartExpiry starts a goroutine which calls Expire at the specified interval, using the system
+// clock.  The returned function stops the goroutine, and waits for it to exit.
+func (s *TTLSet) StartExpiry(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-ticker.C:
+				s.Expire()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// String returns a string representation of this set, with unexpired elements in canonical sorted order
+func (s *TTLSet) String() string {
+	return s.ToSet().String()
+}
+
+// ToSet copies the unexpired elements of the current set into a new Set, without their lifetimes
+func (s *TTLSet) ToSet() *Set {
+	return New(s.Enumerate()...)
+}
+
+// expire removes all elements whose lifetime has passed, returning them in order of expiry.  The
+// caller must hold the lock, and pass the result to notify once the lock is released.
+func (s *TTLSet) expire() []interface{} {
+	// Avoid reading the clock when no elements can expire
+	if len(s.expiries) == 0 {
+		return nil
+	}
+
+	// Remove elements from the top of the heap until one has not expired
+	var expired []interface{}
+	now := s.opts.Now()
+	for len(s.expiries) > 0 && !now.Before(s.expiries[0].expires) {
+		e := heap.Pop(&s.expiries).(*ttlEntry)
+		delete(s.m, e.value)
+		expired = append(expired, e.value)
+	}
+
+	return expired
+}
+
+// notify makes the OnExpire callback for each expired element.  The caller must not hold the lock.
+func (s *TTLSet) notify(expired []interface{}) {
+	if s.opts.OnExpire == nil {
+		return
+	}
+
+	for _, v := range expired {
+		s.opts.OnExpire(v)
+	}
+}
+
+// schedule updates the expiry time of an entry, adding it to, moving it within or removing it from the
+// expiry heap as needed.  The caller must hold the lock.
+func (s *TTLSet) schedule(e *ttlEntry, expires time.Time) {
+	e.expires = expires
+
+	switch {
+	case e.index < 0 && !expires.IsZero():
+		heap.Push(&s.expiries, e)
+	case e.index >= 0 && expires.IsZero():
+		heap.Remove(&s.expiries, e.index)
+	case e.index >= 0:
+		heap.Fix(&s.expiries, e.index)
+	}
+}
+
+// ttlHeap is a min-heap of entries ordered by expiry time, implementing heap.Interface
+type ttlHeap []*ttlEntry
+
+// Len returns the number of entries in the heap
+func (h ttlHeap) Len() int {
+	return len(h)
+}
+
+// Less orders entries by expiry time
+func (h ttlHeap) Less(i int, j int) bool {
+	return h[i].expires.Before(h[j].expires)
+}
+
+// Swap exchanges two entries, updating their indices
+func (h ttlHeap) Swap(i int, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push adds an entry to the end of the heap
+func (h *ttlHeap) Push(x interface{}) {
+	e := x.(*ttlEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+// Pop removes the entry at the end of the heap
+func (h *ttlHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	e.index = -1
+	return e
+}
@@ -0,0 +1,170 @@
+package set
+
+import (
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for testing expiry
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// Now returns the current time of the clock
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// TestTTLSet verifies that elements of a TTLSet expire at the correct time
+func TestTTLSet(t *testing.T) {
+	log.Println("TestTTLSet()")
+
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	var expired []interface{}
+	var s *TTLSet
+	s = NewTTL(TTLOptions{
+		TTL: 10 * time.Second,
+		Now: clock.Now,
+		OnExpire: func(v interface{}) {
+			// Callbacks may safely access the set
+			if s.Has(v) {
+				t.Errorf("set.TTLSet - expired element %v is a member", v)
+			}
+			expired = append(expired, v)
+		},
+	}, "a")
+
+	s.AddTTL("b", 5*time.Second)
+	s.AddTTL("c", 20*time.Second)
+	s.AddTTL("forever", 0)
+
+	if !s.ToSet().Equal(New("a", "b", "c", "forever")) {
+		t.Fatalf("set.TTLSet - unexpected result: %s", s)
+	}
+	if e, ok := s.Expiry("b"); !ok || !e.Equal(time.Unix(1005, 0)) {
+		t.Fatalf("set.TTLSet.Expiry() - unexpected result: %v, %t", e, ok)
+	}
+	if e, ok := s.Expiry("forever"); !ok || !e.IsZero() {
+		t.Fatalf("set.TTLSet.Expiry() - unexpected result: %v, %t", e, ok)
+	}
+
+	// Create a table of clock advances and expected results
+	var tests = []struct {
+		advance time.Duration
+		members *Set
+		expired []interface{}
+	}{
+		// Nothing has expired yet
+		{4 * time.Second, New("a", "b", "c", "forever"), nil},
+		// Expiry is inclusive of the expiry time
+		{1 * time.Second, New("a", "c", "forever"), []interface{}{"b"}},
+		{5 * time.Second, New("c", "forever"), []interface{}{"b", "a"}},
+		// Elements without a lifetime never expire
+		{time.Hour, New("forever"), []interface{}{"b", "a", "c"}},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		clock.Advance(test.advance)
+
+		if s.Size() != test.members.Size() || !New(s.Enumerate()...).Equal(test.members) {
+			t.Fatalf("set.TTLSet - unexpected members: %s != %s", s, test.members)
+		}
+		if len(expired) != len(test.expired) {
+			t.Fatalf("set.TTLSet - unexpected expiries: %v != %v", expired, test.expired)
+		}
+		for i := range expired {
+			if expired[i] != test.expired[i] {
+				t.Fatalf("set.TTLSet - unexpected expiries: %v != %v", expired, test.expired)
+			}
+		}
+	}
+}
+
+// TestTTLSetRefresh verifies that re-adding or removing an element of a TTLSet updates its lifetime
+func TestTTLSetRefresh(t *testing.T) {
+	log.Println("TestTTLSetRefresh()")
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	calls := 0
+	s := NewTTL(TTLOptions{
+		TTL: time.Minute,
+		Now: clock.Now,
+		OnExpire: func(interface{}) {
+			calls++
+		},
+	})
+
+	if !s.Add(1) || s.Add(1) {
+		t.Fatalf("set.TTLSet.Add() - unexpected result")
+	}
+	s.Add(2)
+	s.Add(3)
+
+	// Restart the lifetime of one element, make another permanent, and remove another
+	clock.Advance(30 * time.Second)
+	s.Add(1)
+	s.AddTTL(2, 0)
+	if !s.Remove(3) || s.Remove(3) {
+		t.Fatalf("set.TTLSet.Remove() - unexpected result")
+	}
+
+	clock.Advance(45 * time.Second)
+	if !s.Has(1) || !s.Has(2) || s.Has(3) || calls != 0 {
+		t.Fatalf("set.TTLSet - unexpected result: %s, %d calls", s, calls)
+	}
+
+	clock.Advance(15 * time.Second)
+	if n := s.Expire(); n != 1 || s.Has(1) || !s.Has(2) || calls != 1 {
+		t.Fatalf("set.TTLSet.Expire() - unexpected result: %d, %s, %d calls", n, s, calls)
+	}
+
+	// An expired element can be added again
+	if !s.Add(1) {
+		t.Fatalf("set.TTLSet.Add() - expired element not re-added")
+	}
+}
+
+// TestTTLSetStartExpiry verifies that the ttlset.StartExpiry() method expires elements in the background
+func TestTTLSetStartExpiry(t *testing.T) {
+	log.Println("TestTTLSetStartExpiry()")
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	done := make(chan interface{}, 1)
+	s := NewTTL(TTLOptions{
+		Now: clock.Now,
+		OnExpire: func(v interface{}) {
+			done <- v
+		},
+	})
+	s.AddTTL("x", time.Second)
+
+	stop := s.StartExpiry(time.Millisecond)
+	defer stop()
+
+	clock.Advance(time.Second)
+	select {
+	case v := <-done:
+		if v != "x" {
+			t.Fatalf("set.TTLSet.StartExpiry() - unexpected element expired: %v", v)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("set.TTLSet.StartExpiry() - element not expired")
+	}
+
+	stop()
+}
@@ -0,0 +1,204 @@
+package set
+
+// Tx is a transaction against a Set or CustomSet, which is created by Update or View.  All operations
+// within a transaction are performed under a single lock, so a transaction sees a consistent view of the
+// set, and the changes made by a read-write transaction are applied atomically.
+//
+// A Tx is only valid within the function passed to Update or View, and must not be retained or used
+// from other goroutines.
+type Tx struct {
+	// Storage of the set which the transaction operates on
+	s txStore
+	// Whether or not the transaction may modify the set
+	writable bool
+	// Whether or not the transaction has finished
+	done bool
+	// Changes made by the transaction, in order, used to roll back or notify subscribers
+	changes []Event
+}
+
+// txStore is the storage of a set which supports transactions.  The caller must hold the set's lock,
+// and must detach the set's subscribers while the transaction is in progress.
+type txStore interface {
+	// txAdd inserts a value, returning true if it was newly added, or an error if it cannot be stored
+	txAdd(value interface{}) (bool, error)
+	// txHas checks for membership of a value
+	txHas(value interface{}) bool
+	// txRemove destroys a value, returning true if it existed
+	txRemove(value interface{}) bool
+	// txSize returns the number of elements
+	txSize() int
+}
+
+// Update runs a function within a read-write transaction, holding the set's write lock for the whole
+// of the function.  If the function returns an error or panics, every change made through the
+// transaction is rolled back, and the error is returned.  Subscribers are notified of changes only
+// once the transaction has committed.
+func (s *Set) Update(fn func(tx *Tx) error) error {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return updateTx(s, &s.subs, fn)
+}
+
+// View runs a function within a read-only transaction, holding the set's read lock for the whole of
+// the function.  Calling Add or Remove on a read-only transaction panics.
+func (s *Set) View(fn func(tx *Tx)) {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	viewTx(s, fn)
+}
+
+// updateTx runs a function within a read-write transaction against a set, whose write lock must be held
+// by the caller.  Subscribers are detached until the transaction finishes, and notified only on commit.
+func updateTx(store txStore, subs *[]*subscription, fn func(tx *Tx) error) error {
+	tx := &Tx{
+		s:        store,
+		writable: true,
+	}
+
+	// Detach subscribers, so that neither changes nor their rollback are delivered early
+	attached := *subs
+	*subs = nil
+
+	// Roll back if the function does not complete successfully, including by panicking
+	committed := false
+	defer func() {
+		tx.done = true
+		if !committed {
+			tx.rollback()
+		}
+		*subs = attached
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	// Commit, notifying subscribers of every change in order
+	committed = true
+	for _, e := range tx.changes {
+		notifyAll(attached, e.Kind, e.Value)
+	}
+
+	return nil
+}
+
+// viewTx runs a function within a read-only transaction against a set, whose read lock must be held by
+// the caller
+func viewTx(store txStore, fn func(tx *Tx)) {
+	tx := &Tx{
+		s: store,
+	}
+	defer func() {
+		tx.done = true
+	}()
+
+	fn(tx)
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  Like Set.Add, an element which cannot be stored is not added, and Add returns
+// false; use TryAdd to receive an error instead.
+func (tx *Tx) Add(value interface{}) bool {
+	added, _ := tx.TryAdd(value)
+	return added
+}
+
+// Has checks for membership of an element in the set.  Elements which are not hashable are never members.
+func (tx *Tx) Has(value interface{}) bool {
+	tx.check(false)
+
+	return tx.s.txHas(value)
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it
+// did not exist
+func (tx *Tx) Remove(value interface{}) bool {
+	tx.check(true)
+
+	// Remove value from set, recording the change
+	if !tx.s.txRemove(value) {
+		return false
+	}
+
+	tx.changes = append(tx.changes, Event{
+		Kind:  EventRemove,
+		Value: value,
+	})
+	return true
+}
+
+// Size returns the size or cardinality of the set
+func (tx *Tx) Size() int {
+	tx.check(false)
+
+	return tx.s.txSize()
+}
+
+// TryAdd inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  If the element cannot be stored, such as an unhashable value added to a Set,
+// TryAdd returns an error describing it, and the transaction is unchanged.
+func (tx *Tx) TryAdd(value interface{}) (bool, error) {
+	tx.check(true)
+
+	// Add value to set, recording the change
+	added, err := tx.s.txAdd(value)
+	if !added {
+		return false, err
+	}
+
+	tx.changes = append(tx.changes, Event{
+		Kind:  EventAdd,
+		Value: value,
+	})
+	return true, nil
+}
+
+// check panics if the transaction has finished, or if a write is attempted on a read-only transaction
+func (tx *Tx) check(write bool) {
+	if tx.done {
+		panic("set: transaction used after it has finished")
+	}
+	if write && !tx.writable {
+		panic("set: cannot modify set in a read-only transaction")
+	}
+}
+
+// rollback reverts every change made by the transaction, in reverse order
+func (tx *Tx) rollback() {
+	for i := len(tx.changes) - 1; i >= 0; i-- {
+		e := tx.changes[i]
+		if e.Kind == EventAdd {
+			tx.s.txRemove(e.Value)
+		} else {
+			tx.s.txAdd(e.Value)
+		}
+	}
+
+	tx.changes = nil
+}
+
+// txAdd implements txStore for Set
+func (s *Set) txAdd(value interface{}) (bool, error) {
+	return s.add(value)
+}
+
+// txHas implements txStore for Set
+func (s *Set) txHas(value interface{}) bool {
+	found, _ := s.probe(value)
+	return found
+}
+
+// txRemove implements txStore for Set
+func (s *Set) txRemove(value interface{}) bool {
+	return s.remove(value)
+}
+
+// txSize implements txStore for Set
+func (s *Set) txSize() int {
+	return len(s.m)
+}
@@ -0,0 +1,194 @@
+package set
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"testing"
+)
+
+// TestUpdate verifies that the set.Update() method commits changes on success
+func TestUpdate(t *testing.T) {
+	log.Println("TestUpdate()")
+
+	s := New(1, 2, 3)
+	var events []Event
+	s.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	err := s.Update(func(tx *Tx) error {
+		if !tx.Has(1) {
+			return errors.New("missing 1")
+		}
+
+		tx.Remove(1)
+		tx.Remove(2)
+		tx.Add(4)
+		if tx.Add(4) || tx.Remove(1) || tx.Size() != 2 {
+			t.Errorf("set.Tx - unexpected result within transaction")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("set.Update() - unexpected error: %v", err)
+	}
+
+	if !s.Equal(New(3, 4)) {
+		t.Fatalf("set.Update() - unexpected result: %s", s)
+	}
+	if len(events) != 3 || events[0] != (Event{EventRemove, 1}) || events[2] != (Event{EventAdd, 4}) {
+		t.Fatalf("set.Update() - unexpected events: %v", events)
+	}
+}
+
+// TestUpdateRollback verifies that the set.Update() method rolls back changes on error or panic
+func TestUpdateRollback(t *testing.T) {
+	log.Println("TestUpdateRollback()")
+
+	s := New(1, 2, 3)
+	var events []Event
+	s.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	// Returning an error rolls back all changes, including ones which undo each other
+	errFail := errors.New("fail")
+	err := s.Update(func(tx *Tx) error {
+		tx.Remove(1)
+		tx.Add(4)
+		tx.Add(1)
+		tx.Remove(2)
+		tx.Add(2)
+		return errFail
+	})
+	if err != errFail {
+		t.Fatalf("set.Update() - unexpected error: %v", err)
+	}
+	if !s.Equal(New(1, 2, 3)) || len(events) != 0 {
+		t.Fatalf("set.Update() - changes not rolled back: %s, %v", s, events)
+	}
+
+	// Panicking rolls back all changes, and releases the lock
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("set.Update() - expected panic")
+			}
+		}()
+
+		s.Update(func(tx *Tx) error {
+			tx.Remove(3)
+			panic("boom")
+		})
+	}()
+	if !s.Equal(New(1, 2, 3)) || len(events) != 0 {
+		t.Fatalf("set.Update() - changes not rolled back: %s, %v", s, events)
+	}
+}
+
+// TestView verifies that the set.View() method provides a read-only transaction
+func TestView(t *testing.T) {
+	log.Println("TestView()")
+
+	s := New(1, 2)
+	var tx *Tx
+	s.View(func(v *Tx) {
+		tx = v
+		if !v.Has(1) || v.Has(3) || v.Size() != 2 {
+			t.Errorf("set.View() - unexpected result within transaction")
+		}
+	})
+
+	// Create a table of operations which must panic
+	var tests = []struct {
+		name string
+		fn   func()
+	}{
+		{"Add in View", func() { s.View(func(tx *Tx) { tx.Add(3) }) }},
+		{"Remove in View", func() { s.View(func(tx *Tx) { tx.Remove(1) }) }},
+		{"Has after View", func() { tx.Has(1) }},
+	}
+
+	// Iterate test table, checking for panics
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("set.Tx - %s: expected panic", test.name)
+				}
+			}()
+
+			test.fn()
+		}()
+	}
+}
+
+// TestUpdateConcurrent verifies that transactions are atomic with respect to each other
+func TestUpdateConcurrent(t *testing.T) {
+	log.Println("TestUpdateConcurrent()")
+
+	s := New("token")
+
+	// Many goroutines race to move a single token, and exactly one move is visible at a time
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Update(func(tx *Tx) error {
+					if !tx.Has("token") {
+						return errors.New("token missing")
+					}
+
+					tx.Remove("token")
+					tx.Add(i)
+					tx.Remove(i)
+					tx.Add("token")
+					return nil
+				})
+				s.View(func(tx *Tx) {
+					if tx.Size() != 1 || !tx.Has("token") {
+						t.Errorf("set.View() - inconsistent view of set")
+					}
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestTxUnhashable verifies that transactions report unhashable values without panicking
+func TestTxUnhashable(t *testing.T) {
+	log.Println("TestTxUnhashable()")
+
+	s := New(1)
+	var events []Event
+	s.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	// Adding an unhashable value fails, and rolls back earlier changes when the error is returned
+	err := s.Update(func(tx *Tx) error {
+		tx.Add(2)
+		if tx.Add([]byte("x")) || tx.Has([]byte("x")) || tx.Remove([]byte("x")) {
+			t.Errorf("set.Tx - unexpected result for unhashable value")
+		}
+
+		_, err := tx.TryAdd([]byte("x"))
+		return err
+	})
+	if err == nil {
+		t.Fatalf("set.Tx.TryAdd() - expected error for unhashable value")
+	}
+	if !s.Equal(New(1)) || len(events) != 0 {
+		t.Fatalf("set.Update() - changes not rolled back: %s, %v", s, events)
+	}
+
+	s.View(func(tx *Tx) {
+		if tx.Has([]byte("x")) || tx.Has(map[int]int{}) {
+			t.Errorf("set.View() - unexpected result for unhashable value")
+		}
+	})
+}
@@ -0,0 +1,328 @@
+package set
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TypedSet represents an unordered collection of unique values of type T
+type TypedSet[T comparable] struct {
+	// Mutex to allow safe, concurrent access
+	mutex sync.RWMutex
+	// Empty struct consumes no memory, so we just use the map keys
+	m map[T]struct{}
+}
+
+// NewTyped creates a new TypedSet, and initializes its internal map, optionally adding initial elements
+// to the set
+func NewTyped[T comparable](values ...T) *TypedSet[T] {
+	// Initialize set
+	s := TypedSet[T]{
+		m: make(map[T]struct{}),
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &s
+}
+
+// ToTyped converts an untyped Set into a TypedSet, returning an error if any element of the set is
+// not of type T
+func ToTyped[T comparable](s *Set) (*TypedSet[T], error) {
+	// Create the output set
+	outSet := NewTyped[T]()
+
+	// Enumerate the source set, asserting the type of each element
+	for _, e := range s.Enumerate() {
+		v, ok := e.(T)
+		if !ok {
+			return nil, fmt.Errorf("set: element %v of type %T is not of type %T", e, e, *new(T))
+		}
+
+		outSet.Add(v)
+	}
+
+	return outSet, nil
+}
+
+// Untyped converts the current set into an untyped Set, so it may be used by code which has not
+// yet migrated to TypedSet
+func (s *TypedSet[T]) Untyped() *Set {
+	// Copy set into a new untyped set
+	outSet := New()
+	for _, v := range s.Enumerate() {
+		outSet.Add(v)
+	}
+
+	return outSet
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  The check and insert happen atomically.
+func (s *TypedSet[T]) Add(value T) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence
+	if _, ok := s.m[value]; ok {
+		return false
+	}
+
+	// Add value to set
+	s.m[value] = struct{}{}
+	return true
+}
+
+// TypedPair represents a pair of elements created from a cartesian product of two TypedSets
+type TypedPair[T, U comparable] struct {
+	X T
+	Y U
+}
+
+// String returns a string representation of this pair
+func (p TypedPair[T, U]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.X, p.Y)
+}
+
+// CartesianProductTyped returns a set containing ordered pairs of every permutation between two sets.
+// Since methods cannot introduce new type parameters, this is a function rather than a method.
+func CartesianProductTyped[T, U comparable](s *TypedSet[T], t *TypedSet[U]) *TypedSet[TypedPair[T, U]] {
+	// Create a set of ordered pair permutations between the sets
+	cpSet := NewTyped[TypedPair[T, U]]()
+
+	// Enumerate the source set
+	for _, x := range s.Enumerate() {
+		// Enumerate the target set
+		for _, y := range t.Enumerate() {
+			// Create pair, insert elements, insert into set
+			cpSet.Add(TypedPair[T, U]{
+				X: x,
+				Y: y,
+			})
+		}
+	}
+
+	return cpSet
+}
+
+// Clone copies the current set into a new, identical set
+func (s *TypedSet[T]) Clone() *TypedSet[T] {
+	// Copy set into a new set
+	outSet := NewTyped[T]()
+	for _, v := range s.Enumerate() {
+		outSet.Add(v)
+	}
+
+	return outSet
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *TypedSet[T]) Difference(t *TypedSet[T]) *TypedSet[T] {
+	// Create a set of differences between the sets
+	diffSet := NewTyped[T]()
+
+	// Enumerate and check all elements in the current set
+	for _, e := range s.Enumerate() {
+		// If element is not present in parameter set, add it to diff set
+		if !t.Has(e) {
+			diffSet.Add(e)
+		}
+	}
+
+	return diffSet
+}
+
+// Enumerate returns an unordered slice of all elements in the set
+func (s *TypedSet[T]) Enumerate() []T {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Gather all values into a slice
+	values := make([]T, 0, len(s.m))
+	for k := range s.m {
+		values = append(values, k)
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets have the same length and no differences, meaning they are equal
+func (s *TypedSet[T]) Equal(t *TypedSet[T]) bool {
+	return s.Size() == t.Size() && s.Difference(t).Size() == 0
+}
+
+// Filter applies a function over all elements of the set, and returns all elements which return true
+// when the function is applied
+func (s *TypedSet[T]) Filter(fn func(T) bool) *TypedSet[T] {
+	// Create a set to return with elements which match filter function
+	filterSet := NewTyped[T]()
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, add elements which it matches
+		if fn(e) {
+			filterSet.Add(e)
+		}
+	}
+
+	return filterSet
+}
+
+// Has checks for membership of an element in the set
+func (s *TypedSet[T]) Has(value T) bool {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check for value
+	_, ok := s.m[value]
+	return ok
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *TypedSet[T]) Intersection(t *TypedSet[T]) *TypedSet[T] {
+	// Create a set of intersections between the sets
+	intSet := NewTyped[T]()
+
+	// Enumerate the current set, keeping elements also present in the parameter set
+	for _, e := range s.Enumerate() {
+		if t.Has(e) {
+			intSet.Add(e)
+		}
+	}
+
+	return intSet
+}
+
+// Map applies a function over all elements of the set, and returns the resulting set.  Use MapTyped
+// to map elements into a set of a different type.
+func (s *TypedSet[T]) Map(fn func(T) T) *TypedSet[T] {
+	return MapTyped(s, fn)
+}
+
+// MapTyped applies a function over all elements of a set, and returns the resulting set, which may
+// hold elements of a different type than the source set
+func MapTyped[T, U comparable](s *TypedSet[T], fn func(T) U) *TypedSet[U] {
+	// Create a set to return with function applied
+	mapSet := NewTyped[U]()
+
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		// Apply the function, capture result
+		mapSet.Add(fn(e))
+	}
+
+	return mapSet
+}
+
+// PowerSet generates a set of all possible subsets, given the current set.  Since a TypedSet cannot
+// be used as a map key, subsets are returned as a slice of sets.
+func (s *TypedSet[T]) PowerSet() []*TypedSet[T] {
+	// Start with the empty set
+	pSet := []*TypedSet[T]{NewTyped[T]()}
+
+	// For each element, add a copy of every existing subset with the element added to it
+	for _, e := range s.Enumerate() {
+		for _, p := range pSet {
+			hSet := p.Clone()
+			hSet.Add(e)
+			pSet = append(pSet, hSet)
+		}
+	}
+
+	return pSet
+}
+
+// Reduce applies a function over all elements of the set, accumulating the results into a final result
+// value.  Use ReduceTyped to accumulate into a value of a different type.
+func (s *TypedSet[T]) Reduce(value T, fn func(T, T) T) T {
+	return ReduceTyped(s, value, fn)
+}
+
+// ReduceTyped applies a function over all elements of a set, accumulating the results into a final
+// result value of any type
+func ReduceTyped[T comparable, U any](s *TypedSet[T], value U, fn func(U, T) U) U {
+	// Enumerate all elements and apply the function
+	for _, e := range s.Enumerate() {
+		value = fn(value, e)
+	}
+
+	return value
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *TypedSet[T]) Remove(value T) bool {
+	// Lock set for write
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check existence
+	if _, ok := s.m[value]; !ok {
+		return false
+	}
+
+	// Remove value from set
+	delete(s.m, value)
+	return true
+}
+
+// Size returns the size or cardinality of this set
+func (s *TypedSet[T]) Size() int {
+	// Lock set for read
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.m)
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *TypedSet[T]) String() string {
+	// Take a single snapshot of the elements, and format it
+	elements := s.Enumerate()
+	values := make([]interface{}, 0, len(elements))
+	for _, v := range elements {
+		values = append(values, v)
+	}
+
+	return formatValues(values)
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *TypedSet[T]) Subset(t *TypedSet[T]) bool {
+	// Check if all elements in the parameter set are contained within the set
+	for _, v := range t.Enumerate() {
+		// Check if element is contained, if not, return false
+		if !s.Has(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *TypedSet[T]) SymmetricDifference(t *TypedSet[T]) *TypedSet[T] {
+	return s.Difference(t).Union(t.Difference(s))
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *TypedSet[T]) Union(t *TypedSet[T]) *TypedSet[T] {
+	// Clone the current set into a new set
+	outSet := s.Clone()
+
+	// Enumerate and add all elements from the parameter set
+	for _, e := range t.Enumerate() {
+		outSet.Add(e)
+	}
+
+	return outSet
+}
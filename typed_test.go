@@ -0,0 +1,178 @@
+package set
+
+import (
+	"log"
+	"strconv"
+	"testing"
+)
+
+// TestTypedAddRemove verifies that the TypedSet.Add() and TypedSet.Remove() methods are working properly
+func TestTypedAddRemove(t *testing.T) {
+	log.Println("TestTypedAddRemove()")
+
+	// Create a set, add some initial values
+	set := NewTyped(1, 3, 5)
+
+	// Create a table of tests and expected results for adding and removing elements
+	var tests = []struct {
+		element int
+		add     bool
+		remove  bool
+	}{
+		// New items
+		{2, true, true},
+		{4, true, true},
+		// Existing items
+		{1, false, true},
+		{3, false, true},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		// Attempt to add an element to the set, verify result
+		if ok := set.Add(test.element); ok != test.add {
+			t.Fatalf("set.Add(%d) - unexpected result: %t", test.element, ok)
+		}
+
+		// Attempt to remove an element from the set, verify result
+		if ok := set.Remove(test.element); ok != test.remove {
+			t.Fatalf("set.Remove(%d) - unexpected result: %t", test.element, ok)
+		}
+
+		log.Println(set, "±", test.element)
+	}
+}
+
+// TestTypedAlgebra verifies that the TypedSet set algebra methods are working properly
+func TestTypedAlgebra(t *testing.T) {
+	log.Println("TestTypedAlgebra()")
+
+	// Create a set, add some initial values
+	set := NewTyped(1, 3, 5)
+	other := NewTyped(1, 2, 6)
+
+	// Create a table of tests and expected results of set algebra
+	var tests = []struct {
+		name   string
+		result *TypedSet[int]
+		target *TypedSet[int]
+	}{
+		{"Union", set.Union(other), NewTyped(1, 2, 3, 5, 6)},
+		{"Intersection", set.Intersection(other), NewTyped(1)},
+		{"Difference", set.Difference(other), NewTyped(3, 5)},
+		{"SymmetricDifference", set.SymmetricDifference(other), NewTyped(2, 3, 5, 6)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+
+		log.Println(test.name, "(", set, ",", other, ") =", test.result)
+	}
+
+	// Verify subsets
+	if !set.Subset(NewTyped(1, 5)) || set.Subset(other) {
+		t.Fatalf("set.Subset() - unexpected result")
+	}
+}
+
+// TestTypedFunctional verifies that the TypedSet.Map(), TypedSet.Filter() and TypedSet.Reduce()
+// methods are working properly
+func TestTypedFunctional(t *testing.T) {
+	log.Println("TestTypedFunctional()")
+
+	// Create a set, add some initial values
+	set := NewTyped(1, 2, 3, 4)
+
+	// Square all values
+	if mapSet := set.Map(func(v int) int { return v * v }); !mapSet.Equal(NewTyped(1, 4, 9, 16)) {
+		t.Fatalf("set.Map() - unexpected result: %s", mapSet)
+	}
+
+	// Convert all values to strings
+	strSet := MapTyped(set, strconv.Itoa)
+	if !strSet.Equal(NewTyped("1", "2", "3", "4")) {
+		t.Fatalf("MapTyped() - unexpected result: %s", strSet)
+	}
+
+	// Keep even values
+	if filterSet := set.Filter(func(v int) bool { return v%2 == 0 }); !filterSet.Equal(NewTyped(2, 4)) {
+		t.Fatalf("set.Filter() - unexpected result: %s", filterSet)
+	}
+
+	// Sum all values
+	if sum := set.Reduce(0, func(p int, v int) int { return p + v }); sum != 10 {
+		t.Fatalf("set.Reduce() - unexpected result: %d", sum)
+	}
+
+	// Sum the length of all string values
+	if n := ReduceTyped(strSet, 0, func(p int, v string) int { return p + len(v) }); n != 4 {
+		t.Fatalf("ReduceTyped() - unexpected result: %d", n)
+	}
+}
+
+// TestTypedPowerSet verifies that the TypedSet.PowerSet() method and CartesianProductTyped() function
+// are working properly
+func TestTypedPowerSet(t *testing.T) {
+	log.Println("TestTypedPowerSet()")
+
+	// Create a set, add some initial values
+	set := NewTyped(1, 3, 5)
+
+	// Verify the number of subsets, and that each subset is contained in the set
+	pSet := set.PowerSet()
+	if len(pSet) != 8 {
+		t.Fatalf("set.PowerSet() - unexpected size: %d", len(pSet))
+	}
+	for _, p := range pSet {
+		if !set.Subset(p) {
+			t.Fatalf("set.PowerSet() - not a subset: %s", p)
+		}
+	}
+
+	// Verify the cartesian product
+	product := CartesianProductTyped(NewTyped(1, 2), NewTyped("a"))
+	if !product.Equal(NewTyped(TypedPair[int, string]{1, "a"}, TypedPair[int, string]{2, "a"})) {
+		t.Fatalf("CartesianProductTyped() - unexpected result: %s", product)
+	}
+
+	log.Println("P(", set, ") ->", pSet)
+}
+
+// TestTypedConversion verifies that sets may be converted between Set and TypedSet
+func TestTypedConversion(t *testing.T) {
+	log.Println("TestTypedConversion()")
+
+	// Convert an untyped set to a typed set
+	typed, err := ToTyped[int](New(1, 2, 3))
+	if err != nil {
+		t.Fatalf("ToTyped() - unexpected error: %v", err)
+	}
+	if !typed.Equal(NewTyped(1, 2, 3)) {
+		t.Fatalf("ToTyped() - unexpected result: %s", typed)
+	}
+
+	// Convert it back again
+	if untyped := typed.Untyped(); !untyped.Equal(New(1, 2, 3)) {
+		t.Fatalf("set.Untyped() - unexpected result: %s", untyped)
+	}
+
+	// Mixed types cannot be converted
+	if _, err := ToTyped[int](New(1, "2")); err == nil {
+		t.Fatalf("ToTyped() - expected error for mixed types")
+	}
+}
+
+// TestTypedString verifies that the TypedSet.String() method prints elements in canonical sorted order
+func TestTypedString(t *testing.T) {
+	log.Println("TestTypedString()")
+
+	if s := NewTyped[int]().String(); s != "{ Ø }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+	if s := NewTyped(10, 3, 1, 2).String(); s != "{ 1 2 3 10 }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+}
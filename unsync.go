@@ -0,0 +1,261 @@
+package set
+
+// UnsyncSet represents an unordered collection of unique values, with the same semantics as Set, but
+// without any synchronization.  It avoids the cost of locking on every operation, and so is suited to
+// sets which are only ever accessed by a single goroutine.
+//
+// An UnsyncSet is not safe for concurrent use.  If an UnsyncSet must later be shared between goroutines,
+// use the Locked method to convert it into a Set.
+type UnsyncSet struct {
+	// Empty struct consumes no memory, so we just use the map keys
+	m map[interface{}]struct{}
+}
+
+// NewUnsync creates a new UnsyncSet, and initializes its internal map, optionally adding initial elements
+// to the set.  As with New, initial elements which are not hashable are silently discarded.
+func NewUnsync(values ...interface{}) *UnsyncSet {
+	// Initialize set
+	s := UnsyncSet{
+		m: make(map[interface{}]struct{}, len(values)),
+	}
+
+	// If items are specified in the initializer, immediately add them to the set
+	for _, v := range values {
+		s.add(v)
+	}
+
+	return &s
+}
+
+// Locked converts the current set into a Set which is safe for concurrent use, without copying its
+// elements.  Ownership of the elements is transferred to the returned Set, and the current set is
+// left empty, so that it cannot modify the Set without synchronization.
+func (s *UnsyncSet) Locked() *Set {
+	// Hand the current map to a new Set, and replace it with an empty one
+	out := &Set{
+		m: s.m,
+	}
+	s.m = make(map[interface{}]struct{})
+
+	return out
+}
+
+// Add inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  As with Set.Add, an element which is not hashable is discarded, and Add
+// returns false; use TryAdd to tell the two cases apart.
+func (s *UnsyncSet) Add(value interface{}) bool {
+	added, _ := s.add(value)
+	return added
+}
+
+// CartesianProduct returns a set containing ordered pairs of every permutation between two sets
+func (s *UnsyncSet) CartesianProduct(t *UnsyncSet) *UnsyncSet {
+	// Create a set of ordered pair permutations between the sets
+	cpSet := NewUnsync()
+
+	// Enumerate the source set
+	for x := range s.m {
+		// Enumerate the target set
+		for y := range t.m {
+			// Create pair, insert elements, insert into set
+			cpSet.m[Pair{
+				X: x,
+				Y: y,
+			}] = struct{}{}
+		}
+	}
+
+	return cpSet
+}
+
+// Clone copies the current set into a new, identical set
+func (s *UnsyncSet) Clone() *UnsyncSet {
+	// Copy set into a new set
+	outSet := &UnsyncSet{
+		m: make(map[interface{}]struct{}, len(s.m)),
+	}
+	for k := range s.m {
+		outSet.m[k] = struct{}{}
+	}
+
+	return outSet
+}
+
+// Difference returns a set containing all elements present in this set, but without any elements
+// present in the parameter set
+func (s *UnsyncSet) Difference(t *UnsyncSet) *UnsyncSet {
+	return s.Filter(func(v interface{}) bool {
+		return !t.Has(v)
+	})
+}
+
+// Enumerate returns an unordered slice of all elements in the set
+func (s *UnsyncSet) Enumerate() []interface{} {
+	// Gather all values into a slice
+	values := make([]interface{}, 0, len(s.m))
+	for k := range s.m {
+		values = append(values, k)
+	}
+
+	return values
+}
+
+// Equal returns whether or not two sets have the same length and no differences, meaning they are equal
+func (s *UnsyncSet) Equal(t *UnsyncSet) bool {
+	return len(s.m) == len(t.m) && t.Subset(s)
+}
+
+// Filter applies a function over all elements of the set, and returns all elements which return true
+// when the function is applied
+func (s *UnsyncSet) Filter(fn func(interface{}) bool) *UnsyncSet {
+	// Create a set to return with elements which match filter function
+	filterSet := NewUnsync()
+
+	// Enumerate all elements and apply the function
+	for k := range s.m {
+		// Apply the function, add elements which it matches
+		if fn(k) {
+			filterSet.m[k] = struct{}{}
+		}
+	}
+
+	return filterSet
+}
+
+// Has checks for membership of an element in the set
+func (s *UnsyncSet) Has(value interface{}) bool {
+	found, _ := probeMap(s.m, value)
+	return found
+}
+
+// Intersection returns a set containing all elements present in both the current set and the parameter set
+func (s *UnsyncSet) Intersection(t *UnsyncSet) *UnsyncSet {
+	return s.Filter(t.Has)
+}
+
+// Map applies a function over all elements of the set, and returns the resulting set
+func (s *UnsyncSet) Map(fn func(interface{}) interface{}) *UnsyncSet {
+	// Create a set to return with function applied
+	mapSet := NewUnsync()
+
+	// Enumerate all elements and apply the function
+	for k := range s.m {
+		// Apply the function, capture result
+		mapSet.add(fn(k))
+	}
+
+	return mapSet
+}
+
+// PowerSet generates a set of all possible subsets, given the current set
+func (s *UnsyncSet) PowerSet() *UnsyncSet {
+	// Start with the empty set
+	subsets := []*UnsyncSet{NewUnsync()}
+
+	// For each element, add a copy of every existing subset with the element added to it
+	for k := range s.m {
+		for _, p := range subsets {
+			hSet := p.Clone()
+			hSet.m[k] = struct{}{}
+			subsets = append(subsets, hSet)
+		}
+	}
+
+	// Gather all subsets into a set
+	pSet := NewUnsync()
+	for _, p := range subsets {
+		pSet.m[p] = struct{}{}
+	}
+
+	return pSet
+}
+
+// Reduce applies a function over all elements of the set, accumulating the results into a final result value
+func (s *UnsyncSet) Reduce(value interface{}, fn func(interface{}, interface{}) interface{}) interface{} {
+	// Enumerate all elements and apply the function
+	for k := range s.m {
+		value = fn(value, k)
+	}
+
+	return value
+}
+
+// Remove destroys an element in the set, returning true if the element was destroyed, or false if it did not exist
+func (s *UnsyncSet) Remove(value interface{}) bool {
+	// Check existence
+	if found, _ := probeMap(s.m, value); !found {
+		return false
+	}
+
+	// Remove value from set
+	delete(s.m, value)
+	return true
+}
+
+// Size returns the size or cardinality of this set
+func (s *UnsyncSet) Size() int {
+	return len(s.m)
+}
+
+// String returns a string representation of this set, with elements in canonical sorted order
+func (s *UnsyncSet) String() string {
+	return formatValues(s.Enumerate())
+}
+
+// Subset determines if a parameter set is a subset of elements within this set, returning true if it
+// is a subset, or false if it is not
+func (s *UnsyncSet) Subset(t *UnsyncSet) bool {
+	// Check if all elements in the parameter set are contained within the set
+	for k := range t.m {
+		// Check if element is contained, if not, return false
+		if _, ok := s.m[k]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SymmetricDifference returns a set containing all elements which are not shared between this set
+// and the parameter set
+func (s *UnsyncSet) SymmetricDifference(t *UnsyncSet) *UnsyncSet {
+	return s.Difference(t).Union(t.Difference(s))
+}
+
+// TryAdd inserts a new element into the set, returning true if the element was newly added, or false
+// if it already existed.  Like Set.TryAdd, if the element is not hashable, TryAdd returns an error
+// describing it.
+func (s *UnsyncSet) TryAdd(value interface{}) (bool, error) {
+	return s.add(value)
+}
+
+// Union returns a set containing all elements present in this set, as well as all elements present
+// in the parameter set
+func (s *UnsyncSet) Union(t *UnsyncSet) *UnsyncSet {
+	// Clone the current set into a new set
+	outSet := s.Clone()
+
+	// Enumerate and add all elements from the parameter set
+	for k := range t.m {
+		outSet.m[k] = struct{}{}
+	}
+
+	return outSet
+}
+
+// add inserts a value into the set, returning true if it was newly added, or an error if it is not
+// hashable
+func (s *UnsyncSet) add(value interface{}) (bool, error) {
+	// Check existence
+	found, hashable := probeMap(s.m, value)
+	if !hashable {
+		return false, errUnhashable(value)
+	}
+	if found {
+		return false, nil
+	}
+
+	// Add value to set
+	s.m[value] = struct{}{}
+	return true, nil
+}
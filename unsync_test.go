@@ -0,0 +1,166 @@
+package set
+
+import (
+	"log"
+	"testing"
+)
+
+// TestUnsyncAddRemove verifies that the UnsyncSet.Add() and UnsyncSet.Remove() methods are working properly
+func TestUnsyncAddRemove(t *testing.T) {
+	log.Println("TestUnsyncAddRemove()")
+
+	// Create a set, add some initial values
+	set := NewUnsync(1, 3, 5)
+
+	// Create a table of tests and expected results for adding and removing elements
+	var tests = []struct {
+		element interface{}
+		add     bool
+		remove  bool
+	}{
+		// New items
+		{2, true, true},
+		{4, true, true},
+		// Existing items
+		{1, false, true},
+		{3, false, true},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		// Attempt to add an element to the set, verify result
+		if ok := set.Add(test.element); ok != test.add {
+			t.Fatalf("set.Add(%d) - unexpected result: %t", test.element, ok)
+		}
+
+		// Attempt to remove an element from the set, verify result
+		if ok := set.Remove(test.element); ok != test.remove {
+			t.Fatalf("set.Remove(%d) - unexpected result: %t", test.element, ok)
+		}
+
+		log.Println(set, "±", test.element)
+	}
+
+	if set.Size() != 1 || !set.Has(5) {
+		t.Fatalf("set.Size() - unexpected result: %d", set.Size())
+	}
+}
+
+// TestUnsyncAlgebra verifies that the UnsyncSet set algebra methods are working properly
+func TestUnsyncAlgebra(t *testing.T) {
+	log.Println("TestUnsyncAlgebra()")
+
+	// Create a set, add some initial values
+	set := NewUnsync(1, 3, 5)
+	other := NewUnsync(1, 2, 6)
+
+	// Create a table of tests and expected results of set algebra
+	var tests = []struct {
+		name   string
+		result *UnsyncSet
+		target *UnsyncSet
+	}{
+		{"Union", set.Union(other), NewUnsync(1, 2, 3, 5, 6)},
+		{"Intersection", set.Intersection(other), NewUnsync(1)},
+		{"Difference", set.Difference(other), NewUnsync(3, 5)},
+		{"SymmetricDifference", set.SymmetricDifference(other), NewUnsync(2, 3, 5, 6)},
+		{"CartesianProduct", NewUnsync(1, 2).CartesianProduct(NewUnsync(3)), NewUnsync(Pair{1, 3}, Pair{2, 3})},
+		{"Map", set.Map(func(v interface{}) interface{} { return v.(int) * 2 }), NewUnsync(2, 6, 10)},
+		{"Filter", set.Filter(func(v interface{}) bool { return v.(int) > 1 }), NewUnsync(3, 5)},
+	}
+
+	// Iterate test table, checking results
+	for _, test := range tests {
+		if !test.result.Equal(test.target) {
+			t.Fatalf("set.%s() - sets not equal: %s != %s", test.name, test.result, test.target)
+		}
+
+		log.Println(test.name, "(", set, ",", other, ") =", test.result)
+	}
+
+	// Verify subsets, power sets and reduction
+	if !set.Subset(NewUnsync(1, 5)) || set.Subset(other) {
+		t.Fatalf("set.Subset() - unexpected result")
+	}
+	if size := set.PowerSet().Size(); size != 8 {
+		t.Fatalf("set.PowerSet() - unexpected size: %d", size)
+	}
+	if sum := set.Reduce(0, func(p interface{}, v interface{}) interface{} { return p.(int) + v.(int) }); sum != 9 {
+		t.Fatalf("set.Reduce() - unexpected result: %v", sum)
+	}
+}
+
+// TestUnsyncLocked verifies that the UnsyncSet.Locked() method transfers elements to a Set
+func TestUnsyncLocked(t *testing.T) {
+	log.Println("TestUnsyncLocked()")
+
+	// Create a set, add some initial values, and convert it to a locked set
+	set := NewUnsync(1, 3, 5)
+	locked := set.Locked()
+
+	// The locked set holds all elements, and the unsynchronized set is now empty
+	if !locked.Equal(New(1, 3, 5)) {
+		t.Fatalf("set.Locked() - unexpected result: %s", locked)
+	}
+	if set.Size() != 0 {
+		t.Fatalf("set.Locked() - set not emptied: %s", set)
+	}
+
+	// Modifying the unsynchronized set does not affect the locked set
+	set.Add(7)
+	if locked.Has(7) {
+		t.Fatalf("set.Locked() - locked set shares elements: %s", locked)
+	}
+}
+
+// TestUnsyncString verifies that the UnsyncSet.String() method prints elements in canonical sorted order
+func TestUnsyncString(t *testing.T) {
+	log.Println("TestUnsyncString()")
+
+	if s := NewUnsync().String(); s != "{ Ø }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+	if s := NewUnsync(3, "b", 1, Pair{1, 2}, 2, "a").String(); s != "{ 1 2 3 (1, 2) a b }" {
+		t.Fatalf("set.String() - unexpected result: %s", s)
+	}
+}
+
+// TestUnsyncSemantics verifies that an UnsyncSet gives the same results as a Set for the same operations,
+// including for elements which are not hashable
+func TestUnsyncSemantics(t *testing.T) {
+	log.Println("TestUnsyncSemantics()")
+
+	// Create a table of elements, hashable or not
+	var tests = []interface{}{
+		1,
+		"a",
+		nil,
+		Pair{1, 2},
+		[]byte("x"),
+		map[int]int{},
+		Pair{1, []int{}},
+	}
+
+	// Iterate test table, checking that both sets agree
+	for _, v := range tests {
+		s, u := New(0), NewUnsync(0)
+		if s.Add(v) != u.Add(v) || s.Add(v) != u.Add(v) || s.Has(v) != u.Has(v) {
+			t.Fatalf("set.UnsyncSet.Add(%v) - result differs from set.Add()", v)
+		}
+		_, serr := s.TryAdd(v)
+		_, uerr := u.TryAdd(v)
+		if (serr == nil) != (uerr == nil) {
+			t.Fatalf("set.UnsyncSet.TryAdd(%v) - result differs from set.TryAdd()", v)
+		}
+		if s.Remove(v) != u.Remove(v) || s.Has(v) != u.Has(v) || s.Size() != u.Size() {
+			t.Fatalf("set.UnsyncSet.Remove(%v) - result differs from set.Remove()", v)
+		}
+		if New(v).Size() != NewUnsync(v).Size() {
+			t.Fatalf("set.NewUnsync(%v) - result differs from set.New()", v)
+		}
+		identity := func(interface{}) interface{} { return v }
+		if s.Map(identity).Size() != u.Map(identity).Size() {
+			t.Fatalf("set.UnsyncSet.Map() - result differs from set.Map() for %v", v)
+		}
+	}
+}